@@ -0,0 +1,61 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// ACPKM key meshing (re-keying) for CTR and OMAC modes of operation.
+// RFC 8645.
+package acpkm
+
+import (
+	"crypto/cipher"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+const (
+	KeySize = 32
+)
+
+// Block cipher constructor with the given KeySize-long key. ACPKM
+// re-keys the cipher on each section, so it needs a way to create it.
+type NewCipher func(key []byte) cipher.Block
+
+func NewKuznechik(key []byte) cipher.Block {
+	var k [gost3412.KeySize]byte
+	copy(k[:], key)
+	return gost3412.NewCipher(k)
+}
+
+func NewMagma(key []byte) cipher.Block {
+	var k [gost341264.KeySize]byte
+	copy(k[:], key)
+	return gost341264.NewCipher(k)
+}
+
+// ACPKM transformation: derive the next section key by encrypting
+// D = 0x80 | 0x81 | ... | 0x9F constant with the current one.
+func ACPKM(c cipher.Block) []byte {
+	bs := c.BlockSize()
+	key := make([]byte, KeySize)
+	for i := 0; i < KeySize; i++ {
+		key[i] = 0x80 + byte(i)
+	}
+	for i := 0; i < KeySize; i += bs {
+		c.Encrypt(key[i:i+bs], key[i:i+bs])
+	}
+	return key
+}
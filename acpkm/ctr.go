@@ -0,0 +1,88 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package acpkm
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+// CTR-ACPKM mode of operation: counter mode with the key changing
+// after every section of sectionSize bytes.
+type CTR struct {
+	newCipher   NewCipher
+	c           cipher.Block
+	sectionSize int
+	ctr         []byte
+	gamma       []byte
+	used        int
+	processed   int
+}
+
+// Create CTR-ACPKM stream. IV must be half of the block size long.
+// Section size must be a positive multiple of the block size.
+func NewCTR(newCipher NewCipher, key, iv []byte, sectionSize int) (*CTR, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("Invalid key size")
+	}
+	c := newCipher(key)
+	bs := c.BlockSize()
+	if len(iv) != bs/2 {
+		return nil, errors.New("Invalid IV size")
+	}
+	if sectionSize <= 0 || sectionSize%bs != 0 {
+		return nil, errors.New("Invalid section size")
+	}
+	ctr := make([]byte, bs)
+	copy(ctr, iv)
+	return &CTR{
+		newCipher:   newCipher,
+		c:           c,
+		sectionSize: sectionSize,
+		ctr:         ctr,
+		gamma:       make([]byte, bs),
+		used:        bs,
+	}, nil
+}
+
+func ctrIncr(ctr []byte) {
+	for i := len(ctr) - 1; i >= 0; i-- {
+		ctr[i]++
+		if ctr[i] != 0 {
+			break
+		}
+	}
+}
+
+func (c *CTR) XORKeyStream(dst, src []byte) {
+	bs := len(c.gamma)
+	for i := 0; i < len(src); i++ {
+		if c.used == bs {
+			if c.processed == c.sectionSize {
+				c.c = c.newCipher(ACPKM(c.c))
+				c.processed = 0
+			}
+			c.c.Encrypt(c.gamma, c.ctr)
+			ctrIncr(c.ctr)
+			c.processed += bs
+			c.used = 0
+		}
+		dst[i] = src[i] ^ c.gamma[c.used]
+		c.used++
+	}
+}
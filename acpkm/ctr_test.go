@@ -0,0 +1,173 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package acpkm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"testing/quick"
+)
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var (
+	rfcKey []byte = hexDecode("" +
+		"8899aabbccddeeff0011223344556677" +
+		"fedcba98765432100123456789abcdef",
+	)
+	rfcPT []byte = hexDecode("" +
+		"1122334455667700ffeeddccbbaa9988" +
+		"00112233445566778899aabbcceeff0a" +
+		"112233445566778899aabbcceeff0a00" +
+		"2233445566778899aabbcceeff0a0011" +
+		"33445566778899aabbcceeff0a001122" +
+		"445566778899aabbcceeff0a00112233" +
+		"5566778899aabbcceeff0a0011223344",
+	)
+)
+
+func TestCTRInterface(t *testing.T) {
+	ctr, _ := NewCTR(NewKuznechik, rfcKey, make([]byte, 8), 32)
+	var _ cipher.Stream = ctr
+}
+
+// Test vector taken from RFC 8645 Appendix A.1
+func TestCTRKuznechik(t *testing.T) {
+	pt := rfcPT
+	ctr, err := NewCTR(NewKuznechik, rfcKey, hexDecode("1234567890abcef0"), 32)
+	if err != nil {
+		t.FailNow()
+	}
+	ct := hexDecode("" +
+		"f195d8bec10ed1dbd57b5fa240bda1b8" +
+		"85eee733f6a13e5df33ce4b33c45dee4" +
+		"4bceeb8f646f4c55001706275e85e800" +
+		"587c4df568d094393e4834afd0805046" +
+		"cf30f57686aeece11cfc6c316b8a896e" +
+		"dffd07ec813636460c4f3b743423163e" +
+		"6409a9c282fac8d469d221e7fbd6de5d",
+	)
+	tmp := make([]byte, len(pt))
+	ctr.XORKeyStream(tmp, pt)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	ctr, _ = NewCTR(NewKuznechik, rfcKey, hexDecode("1234567890abcef0"), 32)
+	ctr.XORKeyStream(tmp[:7], tmp[:7])
+	ctr.XORKeyStream(tmp[7:], tmp[7:])
+	if bytes.Compare(tmp, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+// Test vector for RFC 8645 Appendix A.1 Magma example inputs, computed
+// with GnuTLS 3.7.9 ACPKM and Magma implementation: seven sections
+func TestCTRMagma(t *testing.T) {
+	pt := rfcPT[:56]
+	ctr, err := NewCTR(NewMagma, rfcKey, hexDecode("12345678"), 16)
+	if err != nil {
+		t.FailNow()
+	}
+	ct := hexDecode("" +
+		"2ab81deeeb1e4cab68e104c4bd6b94ea" +
+		"c72c67af6c2e5b6b0eafb61770f1b32e" +
+		"a1ae71149eed1382abd467180672ec6f" +
+		"84a2f15b3fca72c1",
+	)
+	tmp := make([]byte, len(pt))
+	ctr.XORKeyStream(tmp, pt)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	ctr, _ = NewCTR(NewMagma, rfcKey, hexDecode("12345678"), 16)
+	ctr.XORKeyStream(tmp[:13], tmp[:13])
+	ctr.XORKeyStream(tmp[13:], tmp[13:])
+	if bytes.Compare(tmp, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+// Within the single section CTR-ACPKM is the ordinary CTR mode, so
+// GOST R 34.13-2015 Appendix A.2 Magma test vector must match
+func TestCTRMagmaSingleSection(t *testing.T) {
+	key := hexDecode("" +
+		"ffeeddccbbaa99887766554433221100" +
+		"f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+	)
+	pt := hexDecode("" +
+		"92def06b3c130a59" +
+		"db54c704f8189d20" +
+		"4a98fb2e67a8024c" +
+		"8912409b17b57e41",
+	)
+	ctr, err := NewCTR(NewMagma, key, hexDecode("12345678"), len(pt))
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(pt))
+	ctr.XORKeyStream(tmp, pt)
+	if bytes.Compare(tmp, hexDecode(""+
+		"4e98110c97b7b93c"+
+		"3e250d93d6e85d69"+
+		"136d868807b2dbef"+
+		"568eb680ab52a12d",
+	)) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCTRInvalidSectionSize(t *testing.T) {
+	iv := make([]byte, 8)
+	if _, err := NewCTR(NewKuznechik, rfcKey, iv, 0); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewCTR(NewKuznechik, rfcKey, iv, 24); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCTRRandom(t *testing.T) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+	f := func(iv [4]byte, pt []byte, split uint8) bool {
+		ct := make([]byte, len(pt))
+		ctr, _ := NewCTR(NewMagma, key, iv[:], 16)
+		ctr.XORKeyStream(ct, pt)
+		s := int(split)
+		if s > len(pt) {
+			s = len(pt)
+		}
+		pt2 := make([]byte, len(pt))
+		ctr, _ = NewCTR(NewMagma, key, iv[:], 16)
+		ctr.XORKeyStream(pt2[:s], ct[:s])
+		ctr.XORKeyStream(pt2[s:], ct[s:])
+		return bytes.Compare(pt2, pt) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
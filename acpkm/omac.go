@@ -0,0 +1,157 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package acpkm
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/martinlindhe/gogost/gost3413"
+)
+
+// OMAC-ACPKM message authentication code: OMAC with the key (and K1
+// subkey) changing after every section of sectionSize bytes. Section
+// keys are derived with ACPKM-Master function from the master key.
+// Only the current section's key material is kept: ACPKM-Master is
+// restarted on Reset.
+type OMAC struct {
+	newCipher         NewCipher
+	key               []byte
+	bs                int
+	size              int
+	sectionSize       int
+	masterSectionSize int
+	master            *CTR
+	section           int
+	c                 cipher.Block
+	k1                []byte
+	prev              []byte
+	buf               []byte
+	blocks            int
+}
+
+// ACPKM-Master key derivation function: CTR-ACPKM encryption of zero
+// string with all-ones half-block IV and masterSectionSize sections.
+func NewMaster(newCipher NewCipher, key []byte, masterSectionSize int) (*CTR, error) {
+	bs := newCipher(make([]byte, KeySize)).BlockSize()
+	iv := make([]byte, bs/2)
+	for i := 0; i < len(iv); i++ {
+		iv[i] = 0xFF
+	}
+	return NewCTR(newCipher, key, iv, masterSectionSize)
+}
+
+// Create OMAC-ACPKM with given tag size. Tag size is in bytes and must
+// be between 1 and the block size. Section size (N) and master
+// section size (T*) must be positive multiples of the block size.
+func NewOMAC(newCipher NewCipher, key []byte, sectionSize, masterSectionSize, size int) (*OMAC, error) {
+	master, err := NewMaster(newCipher, key, masterSectionSize)
+	if err != nil {
+		return nil, err
+	}
+	bs := len(master.gamma)
+	if sectionSize <= 0 || sectionSize%bs != 0 {
+		return nil, errors.New("Invalid section size")
+	}
+	if size <= 0 || size > bs {
+		return nil, errors.New("Invalid tag size")
+	}
+	m := OMAC{
+		newCipher:         newCipher,
+		key:               append([]byte{}, key...),
+		bs:                bs,
+		size:              size,
+		sectionSize:       sectionSize,
+		masterSectionSize: masterSectionSize,
+		master:            master,
+		k1:                make([]byte, bs),
+		prev:              make([]byte, bs),
+	}
+	m.nextSection()
+	return &m, nil
+}
+
+// Derive the next section's cipher and K1 subkey with ACPKM-Master.
+func (m *OMAC) nextSection() {
+	keys := make([]byte, KeySize+m.bs)
+	m.master.XORKeyStream(keys, keys)
+	m.c = m.newCipher(keys[:KeySize])
+	copy(m.k1, keys[KeySize:])
+}
+
+// Switch to the section the next block belongs to.
+func (m *OMAC) rekey() {
+	for m.section < m.blocks*m.bs/m.sectionSize {
+		m.nextSection()
+		m.section++
+	}
+}
+
+func (m *OMAC) Reset() {
+	for i := 0; i < m.bs; i++ {
+		m.prev[i] = 0
+	}
+	m.buf = nil
+	m.blocks = 0
+	if m.section > 0 {
+		m.master, _ = NewMaster(m.newCipher, m.key, m.masterSectionSize)
+		m.section = 0
+		m.nextSection()
+	}
+}
+
+func (m *OMAC) BlockSize() int {
+	return m.bs
+}
+
+func (m *OMAC) Size() int {
+	return m.size
+}
+
+func (m *OMAC) Write(b []byte) (int, error) {
+	m.buf = append(m.buf, b...)
+	// The last block is kept in the buffer until Sum, because it is
+	// processed differently
+	for len(m.buf) > m.bs {
+		m.rekey()
+		for i := 0; i < m.bs; i++ {
+			m.prev[i] ^= m.buf[i]
+		}
+		m.c.Encrypt(m.prev, m.prev)
+		m.buf = m.buf[m.bs:]
+		m.blocks++
+	}
+	return len(b), nil
+}
+
+func (m *OMAC) Sum(b []byte) []byte {
+	m.rekey()
+	k := make([]byte, m.bs)
+	copy(k, m.k1)
+	buf := make([]byte, m.bs)
+	copy(buf, m.buf)
+	if len(m.buf) != m.bs {
+		buf[len(m.buf)] = 0x80
+		gost3413.ShiftSubkey(k, k)
+	}
+	for i := 0; i < m.bs; i++ {
+		buf[i] ^= m.prev[i] ^ k[i]
+	}
+	m.c.Encrypt(buf, buf)
+	return append(b, buf[:m.size]...)
+}
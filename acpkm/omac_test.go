@@ -0,0 +1,104 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package acpkm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"hash"
+	"testing"
+	"testing/quick"
+)
+
+func TestOMACInterface(t *testing.T) {
+	m, _ := NewOMAC(NewKuznechik, rfcKey, 32, 96, 16)
+	var _ hash.Hash = m
+}
+
+// Test vectors taken from RFC 8645 Appendix A.2
+func TestOMACKuznechik(t *testing.T) {
+	pt := rfcPT[:80]
+	m, err := NewOMAC(NewKuznechik, rfcKey, 32, 96, 16)
+	if err != nil {
+		t.FailNow()
+	}
+	m.Write(pt[:24])
+	if bytes.Compare(m.Sum(nil), hexDecode("b5367f47b62b995eeb2a648c5843145e")) != 0 {
+		t.FailNow()
+	}
+	m.Reset()
+	m.Write(pt)
+	if bytes.Compare(m.Sum(nil), hexDecode("fbb8dcee45bea67c35f58c5700898e5d")) != 0 {
+		t.FailNow()
+	}
+}
+
+// Test vectors for RFC 8645 Appendix A.2 Magma example inputs, computed
+// with GnuTLS 3.7.9 ACPKM and Magma implementation
+func TestOMACMagma(t *testing.T) {
+	m, err := NewOMAC(NewMagma, rfcKey, 16, 80, 8)
+	if err != nil {
+		t.FailNow()
+	}
+	m.Write(rfcPT[:12])
+	if bytes.Compare(m.Sum(nil), hexDecode("a0540e3730acbcf3")) != 0 {
+		t.FailNow()
+	}
+	m.Reset()
+	m.Write(rfcPT[:40])
+	if bytes.Compare(m.Sum(nil), hexDecode("34008dad5496bb8e")) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestOMACReset(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.Read(data)
+	m, _ := NewOMAC(NewMagma, rfcKey, 16, 40*3, 8)
+	m.Write(data)
+	tag := m.Sum(nil)
+	m.Reset()
+	m.Write(data)
+	if bytes.Compare(m.Sum(nil), tag) != 0 {
+		t.FailNow()
+	}
+	m, _ = NewOMAC(NewMagma, rfcKey, 16, 40*3, 8)
+	m.Write(data)
+	if bytes.Compare(m.Sum(nil), tag) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestOMACRandom(t *testing.T) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+	m, _ := NewOMAC(NewMagma, key, 16, 40*3, 8)
+	f := func(data []byte) bool {
+		m.Reset()
+		m.Write(data)
+		tag1 := m.Sum(nil)
+		m.Reset()
+		for _, b := range data {
+			m.Write([]byte{b})
+		}
+		return bytes.Compare(tag1, m.Sum(nil)) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,171 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Cryptographic Message Syntax (RFC 5652) with GOST algorithms
+// (RFC 4490, RFC 9337).
+package cms
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/martinlindhe/gogost/x509"
+)
+
+var (
+	oidData       asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue // [0] EXPLICIT
+}
+
+// Explicitly tagged value. encoding/asn1 ignores explicit tag of
+// RawValue with FullBytes, so it is made manually.
+func explicit(tag int, der []byte) asn1.RawValue {
+	return asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        tag,
+		IsCompound: true,
+		Bytes:      der,
+	}
+}
+
+func unexplicit(rv asn1.RawValue, tag int) ([]byte, error) {
+	if rv.Class != asn1.ClassContextSpecific || rv.Tag != tag || !rv.IsCompound {
+		return nil, errors.New("Unexpected tag")
+	}
+	return rv.Bytes, nil
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+func newAttribute(typ asn1.ObjectIdentifier, v interface{}) (attribute, error) {
+	raw, err := asn1.Marshal(v)
+	if err != nil {
+		return attribute{}, err
+	}
+	return attribute{typ, []asn1.RawValue{{FullBytes: raw}}}, nil
+}
+
+// DER encoded SET OF attributes, sorted by their encoding.
+func marshalAttributes(attrs []attribute) ([]byte, error) {
+	encoded := make([][]byte, 0, len(attrs))
+	for _, attr := range attrs {
+		raw, err := asn1.Marshal(attr)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, raw)
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return string(encoded[i]) < string(encoded[j])
+	})
+	var content []byte
+	for _, raw := range encoded {
+		content = append(content, raw...)
+	}
+	return asn1.Marshal(asn1.RawValue{
+		Tag:        asn1.TagSet,
+		IsCompound: true,
+		Bytes:      content,
+	})
+}
+
+// Find the single value of the attribute. Nil is returned if
+// attribute is absent.
+func findAttribute(attrs []attribute, typ asn1.ObjectIdentifier) ([]byte, error) {
+	var found []byte
+	for _, attr := range attrs {
+		if !attr.Type.Equal(typ) {
+			continue
+		}
+		if found != nil || len(attr.Values) != 1 {
+			return nil, errors.New("Invalid attribute")
+		}
+		found = attr.Values[0].FullBytes
+	}
+	return found, nil
+}
+
+func marshalContentInfo(typ asn1.ObjectIdentifier, content []byte) ([]byte, error) {
+	return asn1.Marshal(contentInfo{typ, explicit(0, content)})
+}
+
+func parseContentInfo(der []byte, typ asn1.ObjectIdentifier) ([]byte, error) {
+	var ci contentInfo
+	rest, err := asn1.Unmarshal(der, &ci)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after content info")
+	}
+	if !ci.ContentType.Equal(typ) {
+		return nil, errors.New("Unexpected content type")
+	}
+	return unexplicit(ci.Content, 0)
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// SignerIdentifier or RecipientIdentifier with issuer and serial number.
+func marshalIdentifier(cert *x509.Certificate) (asn1.RawValue, error) {
+	raw, err := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+		SerialNumber: cert.SerialNumber,
+	})
+	return asn1.RawValue{FullBytes: raw}, err
+}
+
+// Parse SignerIdentifier or RecipientIdentifier: either issuer and
+// serial number, or subject key identifier.
+func parseIdentifier(rv asn1.RawValue) ([]byte, *big.Int, []byte, error) {
+	switch rv.Class {
+	case asn1.ClassUniversal:
+		var ias issuerAndSerialNumber
+		if _, err := asn1.Unmarshal(rv.FullBytes, &ias); err != nil {
+			return nil, nil, nil, err
+		}
+		return ias.Issuer.FullBytes, ias.SerialNumber, nil, nil
+	case asn1.ClassContextSpecific:
+		if rv.Tag == 0 && !rv.IsCompound {
+			return nil, nil, rv.Bytes, nil
+		}
+	}
+	return nil, nil, nil, errors.New("Invalid identifier")
+}
+
+// Does the identifier refer to the certificate.
+func identifies(cert *x509.Certificate, rawIssuer []byte, serial *big.Int, ski []byte) bool {
+	if ski != nil {
+		return bytes.Equal(ski, cert.SubjectKeyId)
+	}
+	return bytes.Equal(rawIssuer, cert.RawIssuer) &&
+		serial.Cmp(cert.SerialNumber) == 0
+}
@@ -0,0 +1,628 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cms
+
+import (
+	"crypto/cipher"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/acpkm"
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/gost3413"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+var oidEnvelopedData asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+
+const (
+	// UKM length for KExp15 key export: 16 bytes for VKO, 8 bytes of
+	// KDF_TREE seed and the IV
+	kexp15UKMSize = 32
+)
+
+type envelopedData struct {
+	Version              int
+	OriginatorInfo       asn1.RawValue   `asn1:"optional,tag:0"`
+	RecipientInfos       []asn1.RawValue `asn1:"set"`
+	EncryptedContentInfo encryptedContentInfo
+	UnprotectedAttrs     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"optional,tag:0"`
+}
+
+type keyTransRecipientInfo struct {
+	Version                int
+	RID                    asn1.RawValue
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type keyAgreeRecipientInfo struct {
+	Version                int
+	Originator             asn1.RawValue // [0] EXPLICIT
+	UKM                    []byte        `asn1:"optional,explicit,tag:1"`
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	RecipientEncryptedKeys []recipientEncryptedKey
+}
+
+type recipientEncryptedKey struct {
+	RID          asn1.RawValue
+	EncryptedKey []byte
+}
+
+// Gost28147-89-EncryptedKey
+type gost2814789EncryptedKey struct {
+	EncryptedKey []byte
+	MaskKey      []byte `asn1:"optional,tag:0"`
+	MACKey       []byte
+}
+
+// GostR3410-TransportParameters
+type transportParams struct {
+	EncryptionParamSet asn1.ObjectIdentifier
+	EphemeralPublicKey asn1.RawValue `asn1:"optional,tag:0"`
+	UKM                []byte
+}
+
+// GostR3410-KeyTransport for 28147-89 key wrapping (RFC 4490)
+type keyTransport2814789 struct {
+	SessionEncryptedKey asn1.RawValue   // Gost28147-89-EncryptedKey
+	TransportParameters transportParams `asn1:"optional,tag:0"`
+}
+
+// GostR3410-KeyTransport for KExp15 key export
+type keyTransportKExp15 struct {
+	EncryptedKey       []byte
+	EphemeralPublicKey asn1.RawValue
+	UKM                []byte
+}
+
+// Gost28147-89-KeyWrapParameters
+type keyWrapParams struct {
+	EncryptionParamSet asn1.ObjectIdentifier
+	UKM                []byte `asn1:"optional"`
+}
+
+// EnvelopedData creation options.
+type EncryptOptions struct {
+	// Content encryption algorithm: oid.Gost2814789 (default) in CFB
+	// mode with CryptoPro key meshing, oid.KuznyechikCTRACPKM or
+	// oid.MagmaCTRACPKM
+	Algorithm oid.Algorithm
+
+	// Use KeyAgreeRecipientInfo instead of KeyTransRecipientInfo
+	KeyAgreement bool
+}
+
+// Content encryption scheme together with the key wrapping
+// algorithm. S-box is used for 28147-89 key wrapping.
+type contentCipher struct {
+	algo      oid.Algorithm
+	newCipher acpkm.NewCipher // nil for 28147-89
+	wrapAlgo  oid.Algorithm
+	sbox      *gost28147.Sbox
+	scheme    *x509.EncryptionScheme
+}
+
+func newContentCipher(algo oid.Algorithm) (*contentCipher, error) {
+	switch algo {
+	case oid.Unknown, oid.Gost2814789:
+		return &contentCipher{
+			algo:     oid.Gost2814789,
+			wrapAlgo: oid.Gost2814789CryptoProKeyWrap,
+			sbox:     &gost28147.Gost28147_tc26_ParamZ,
+		}, nil
+	case oid.KuznyechikCTRACPKM:
+		return &contentCipher{
+			algo:      algo,
+			newCipher: acpkm.NewKuznechik,
+			wrapAlgo:  oid.KuznyechikKExp15,
+		}, nil
+	case oid.MagmaCTRACPKM:
+		return &contentCipher{
+			algo:      algo,
+			newCipher: acpkm.NewMagma,
+			wrapAlgo:  oid.MagmaKExp15,
+		}, nil
+	}
+	return nil, errors.New("Unsupported content encryption algorithm")
+}
+
+// KExp15 IV size: half of the block.
+func (cc *contentCipher) ivSize() int {
+	return cc.newCipher(make([]byte, acpkm.KeySize)).BlockSize() / 2
+}
+
+func (cc *contentCipher) ukmSize() int {
+	if cc.newCipher == nil {
+		return gost28147.UKMSize
+	}
+	return kexp15UKMSize
+}
+
+func parseContentCipher(ai pkix.AlgorithmIdentifier) (*contentCipher, error) {
+	scheme, err := x509.ParseEncryptionScheme(ai)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := newContentCipher(scheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	cc.scheme = scheme
+	return cc, nil
+}
+
+// KEK for 28147-89 key wrapping: VKO GOST R 34.10-2001 for 34.10-2001
+// keys and VKO GOST R 34.10-2012 256-bit otherwise.
+func kek2814789(prv *gost3410.PrivateKey, pub *gost3410.PublicKey, keyAlgo oid.Algorithm, ukm []byte) ([]byte, error) {
+	if keyAlgo == oid.GostR34102001 {
+		return prv.KEK2001(pub, gost3410.NewUKM(ukm))
+	}
+	return prv.KEK2012256(pub, gost3410.NewUKM(ukm))
+}
+
+// KExp15 MAC and encryption keys: VKO GOST R 34.10-2012 256-bit with
+// UKM[:16], then KDF_TREE_GOSTR3411_2012_256 with UKM[16:24] seed.
+func kexp15Keys(prv *gost3410.PrivateKey, pub *gost3410.PublicKey, ukm []byte) (macKey, encKey []byte, err error) {
+	kek, err := prv.KEK2012256(pub, gost3410.NewUKM(ukm[:16]))
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := gost34112012256.KDFTree(kek, []byte("kdf tree"), ukm[16:24], 1, 64)
+	return keys[:32], keys[32:], nil
+}
+
+// KExp15 encryption and MAC ciphers with the IV taken from the UKM's
+// tail.
+func (cc *contentCipher) kexp15Ciphers(prv *gost3410.PrivateKey, pub *gost3410.PublicKey, ukm []byte) (enc, mac cipher.Block, iv []byte, err error) {
+	macKey, encKey, err := kexp15Keys(prv, pub, ukm)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cc.newCipher(encKey), cc.newCipher(macKey), ukm[24 : 24+cc.ivSize()], nil
+}
+
+// Wrap CEK for the recipient's public key using ephemeral private key.
+// 28147-89 wrapped key is returned as Gost28147-89-EncryptedKey.
+func (cc *contentCipher) wrap(prv *gost3410.PrivateKey, pub *gost3410.PublicKey, keyAlgo oid.Algorithm, ukm, cek []byte) ([]byte, error) {
+	if cc.newCipher != nil {
+		enc, mac, iv, err := cc.kexp15Ciphers(prv, pub, ukm)
+		if err != nil {
+			return nil, err
+		}
+		return gost3413.KExp15(enc, mac, iv, cek)
+	}
+	kek, err := kek2814789(prv, pub, keyAlgo, ukm)
+	if err != nil {
+		return nil, err
+	}
+	var key [gost28147.KeySize]byte
+	var ukmArr [gost28147.UKMSize]byte
+	var cekArr [gost28147.KeySize]byte
+	copy(key[:], kek)
+	copy(ukmArr[:], ukm)
+	copy(cekArr[:], cek)
+	wrapped := gost28147.NewCipher(key, cc.sbox).WrapCryptoPro(ukmArr, cekArr)
+	return asn1.Marshal(gost2814789EncryptedKey{
+		EncryptedKey: wrapped[gost28147.UKMSize : gost28147.UKMSize+gost28147.KeySize],
+		MACKey:       wrapped[gost28147.UKMSize+gost28147.KeySize:],
+	})
+}
+
+// Inverse of wrap.
+func (cc *contentCipher) unwrap(prv *gost3410.PrivateKey, pub *gost3410.PublicKey, keyAlgo oid.Algorithm, ukm, wrapped []byte) ([]byte, error) {
+	if len(ukm) != cc.ukmSize() {
+		return nil, errors.New("Invalid UKM length")
+	}
+	if !pub.Curve().Equal(prv.Curve()) || pub.Mode() != prv.Mode() {
+		return nil, errors.New("Ephemeral key does not match private key")
+	}
+	if cc.newCipher != nil {
+		enc, mac, iv, err := cc.kexp15Ciphers(prv, pub, ukm)
+		if err != nil {
+			return nil, err
+		}
+		return gost3413.KImp15(enc, mac, iv, wrapped)
+	}
+	var ek gost2814789EncryptedKey
+	rest, err := asn1.Unmarshal(wrapped, &ek)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after encrypted key")
+	}
+	if len(ek.MaskKey) > 0 {
+		return nil, errors.New("Masked keys are not supported")
+	}
+	kek, err := kek2814789(prv, pub, keyAlgo, ukm)
+	if err != nil {
+		return nil, err
+	}
+	var key [gost28147.KeySize]byte
+	copy(key[:], kek)
+	wrappedFull := make([]byte, 0, gost28147.WrapSize)
+	wrappedFull = append(wrappedFull, ukm...)
+	wrappedFull = append(wrappedFull, ek.EncryptedKey...)
+	wrappedFull = append(wrappedFull, ek.MACKey...)
+	return gost28147.NewCipher(key, cc.sbox).UnwrapCryptoPro(wrappedFull)
+}
+
+// Key agreement algorithm for the recipient's public key algorithm.
+func agreementAlgorithm(keyAlgo oid.Algorithm) oid.Algorithm {
+	switch keyAlgo {
+	case oid.GostR34102012256:
+		return oid.GostR34102012256Agreement
+	case oid.GostR34102012512:
+		return oid.GostR34102012512Agreement
+	}
+	return keyAlgo
+}
+
+func (cc *contentCipher) marshalKeyWrapAlgorithm() ([]byte, error) {
+	ai := pkix.AlgorithmIdentifier{Algorithm: cc.wrapAlgo.OID()}
+	if cc.newCipher == nil {
+		params, err := asn1.Marshal(keyWrapParams{
+			EncryptionParamSet: oid.SboxOID(cc.sbox),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ai.Parameters = asn1.RawValue{FullBytes: params}
+	}
+	return asn1.Marshal(ai)
+}
+
+// Replace the tag of DER encoded SEQUENCE with IMPLICIT context
+// specific one, or vice versa.
+func retag(der []byte, tag byte) []byte {
+	retagged := append([]byte{}, der...)
+	retagged[0] = tag
+	return retagged
+}
+
+func (cc *contentCipher) newRecipientInfo(
+	rand io.Reader,
+	cert *x509.Certificate,
+	cek []byte,
+	keyAgreement bool,
+) (asn1.RawValue, error) {
+	var ri asn1.RawValue
+	if cert.PublicKey == nil {
+		return ri, errors.New("Unsupported recipient's public key algorithm")
+	}
+	if cc.newCipher != nil && cert.PublicKeyAlgorithm == oid.GostR34102001 {
+		return ri, errors.New("KExp15 requires 34.10-2012 recipient's key")
+	}
+	rid, err := marshalIdentifier(cert)
+	if err != nil {
+		return ri, err
+	}
+	eph, err := gost3410.GenPrivateKey(cert.PublicKey.Curve(), cert.PublicKey.Mode(), rand)
+	if err != nil {
+		return ri, err
+	}
+	ephPub, err := eph.PublicKey()
+	if err != nil {
+		return ri, err
+	}
+	spki, err := x509.MarshalPKIXPublicKey(ephPub, cert.PublicKeyAlgorithm)
+	if err != nil {
+		return ri, err
+	}
+	ukm := make([]byte, cc.ukmSize())
+	if _, err = io.ReadFull(rand, ukm); err != nil {
+		return ri, err
+	}
+	wrapped, err := cc.wrap(eph, cert.PublicKey, cert.PublicKeyAlgorithm, ukm, cek)
+	if err != nil {
+		return ri, err
+	}
+
+	if keyAgreement {
+		wrapAlgo, err := cc.marshalKeyWrapAlgorithm()
+		if err != nil {
+			return ri, err
+		}
+		kari, err := asn1.Marshal(keyAgreeRecipientInfo{
+			Version:    3,
+			Originator: explicit(0, retag(spki, 0xA1)),
+			UKM:        ukm,
+			KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  agreementAlgorithm(cert.PublicKeyAlgorithm).OID(),
+				Parameters: asn1.RawValue{FullBytes: wrapAlgo},
+			},
+			RecipientEncryptedKeys: []recipientEncryptedKey{{rid, wrapped}},
+		})
+		ri.FullBytes = retag(kari, 0xA1)
+		return ri, err
+	}
+
+	var encryptedKey []byte
+	if cc.newCipher == nil {
+		encryptedKey, err = asn1.Marshal(keyTransport2814789{
+			SessionEncryptedKey: asn1.RawValue{FullBytes: wrapped},
+			TransportParameters: transportParams{
+				EncryptionParamSet: oid.SboxOID(cc.sbox),
+				EphemeralPublicKey: asn1.RawValue{FullBytes: retag(spki, 0xA0)},
+				UKM:                ukm,
+			},
+		})
+	} else {
+		encryptedKey, err = asn1.Marshal(keyTransportKExp15{
+			EncryptedKey:       wrapped,
+			EphemeralPublicKey: asn1.RawValue{FullBytes: spki},
+			UKM:                ukm,
+		})
+	}
+	if err != nil {
+		return ri, err
+	}
+	ri.FullBytes, err = asn1.Marshal(keyTransRecipientInfo{
+		RID: rid,
+		KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm: cert.PublicKeyAlgorithm.OID(),
+		},
+		EncryptedKey: encryptedKey,
+	})
+	return ri, err
+}
+
+// Create EnvelopedData for the recipients, wrapped in ContentInfo.
+// Random content encryption key is transported to each recipient
+// using VKO with the ephemeral key on the recipient's curve. For
+// 28147-89 content encryption CEK is wrapped with CryptoPro key wrap
+// (RFC 4490), for CTR-ACPKM ones it is exported with KExp15.
+func Encrypt(
+	rand io.Reader,
+	content []byte,
+	recipients []*x509.Certificate,
+	opts *EncryptOptions,
+) ([]byte, error) {
+	if opts == nil {
+		opts = &EncryptOptions{}
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("No recipients")
+	}
+	cc, err := newContentCipher(opts.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	cek := make([]byte, acpkm.KeySize)
+	if _, err = io.ReadFull(rand, cek); err != nil {
+		return nil, err
+	}
+	if cc.scheme, err = x509.NewEncryptionScheme(cc.algo, rand); err != nil {
+		return nil, err
+	}
+	ed := envelopedData{Version: 0}
+	if opts.KeyAgreement {
+		ed.Version = 2
+	}
+	for _, cert := range recipients {
+		ri, err := cc.newRecipientInfo(rand, cert, cek, opts.KeyAgreement)
+		if err != nil {
+			return nil, err
+		}
+		ed.RecipientInfos = append(ed.RecipientInfos, ri)
+	}
+	ai, err := cc.scheme.AlgorithmIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]byte, len(content))
+	if err = cc.scheme.Encrypt(cek, encrypted, content); err != nil {
+		return nil, err
+	}
+	ed.EncryptedContentInfo = encryptedContentInfo{
+		ContentType:                oidData,
+		ContentEncryptionAlgorithm: ai,
+		EncryptedContent:           encrypted,
+	}
+	der, err := asn1.Marshal(ed)
+	if err != nil {
+		return nil, err
+	}
+	return marshalContentInfo(oidEnvelopedData, der)
+}
+
+// Parsed EnvelopedData.
+type EnvelopedData struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm oid.Algorithm
+
+	cc               *contentCipher
+	encryptedContent []byte
+	recipientInfos   []asn1.RawValue
+}
+
+// Parse DER encoded ContentInfo with EnvelopedData.
+func ParseEnvelopedData(der []byte) (*EnvelopedData, error) {
+	raw, err := parseContentInfo(der, oidEnvelopedData)
+	if err != nil {
+		return nil, err
+	}
+	var ed envelopedData
+	rest, err := asn1.Unmarshal(raw, &ed)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after enveloped data")
+	}
+	eci := &ed.EncryptedContentInfo
+	cc, err := parseContentCipher(eci.ContentEncryptionAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if eci.EncryptedContent == nil {
+		return nil, errors.New("Detached encrypted content is not supported")
+	}
+	return &EnvelopedData{
+		ContentType:                eci.ContentType,
+		ContentEncryptionAlgorithm: cc.algo,
+		cc:                         cc,
+		encryptedContent:           eci.EncryptedContent,
+		recipientInfos:             ed.RecipientInfos,
+	}, nil
+}
+
+func parseSPKI(der []byte) (*gost3410.PublicKey, error) {
+	pub, _, err := x509.ParsePKIXPublicKey(der)
+	return pub, err
+}
+
+// Unwrap CEK from KeyTransRecipientInfo. Nil is returned if it is not
+// for the certificate or its recipient can not be identified.
+func (ed *EnvelopedData) unwrapKeyTrans(ri []byte, cert *x509.Certificate, prv *gost3410.PrivateKey) ([]byte, error) {
+	var ktri keyTransRecipientInfo
+	if _, err := asn1.Unmarshal(ri, &ktri); err != nil {
+		return nil, nil
+	}
+	rawIssuer, serial, ski, err := parseIdentifier(ktri.RID)
+	if err != nil || !identifies(cert, rawIssuer, serial, ski) {
+		return nil, nil
+	}
+	cc := *ed.cc
+	if cc.newCipher != nil {
+		var kt keyTransportKExp15
+		if _, err = asn1.Unmarshal(ktri.EncryptedKey, &kt); err != nil {
+			return nil, err
+		}
+		eph, err := parseSPKI(kt.EphemeralPublicKey.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		return cc.unwrap(prv, eph, cert.PublicKeyAlgorithm, kt.UKM, kt.EncryptedKey)
+	}
+	var kt keyTransport2814789
+	if _, err = asn1.Unmarshal(ktri.EncryptedKey, &kt); err != nil {
+		return nil, err
+	}
+	params := &kt.TransportParameters
+	if len(params.EphemeralPublicKey.FullBytes) == 0 {
+		return nil, errors.New("No ephemeral public key")
+	}
+	eph, err := parseSPKI(retag(params.EphemeralPublicKey.FullBytes, 0x30))
+	if err != nil {
+		return nil, err
+	}
+	if cc.sbox = oid.SboxByOID(params.EncryptionParamSet); cc.sbox == nil {
+		return nil, errors.New("Unknown S-box")
+	}
+	return cc.unwrap(prv, eph, cert.PublicKeyAlgorithm, params.UKM, kt.SessionEncryptedKey.FullBytes)
+}
+
+// Unwrap CEK from KeyAgreeRecipientInfo. Nil is returned if it is not
+// for the certificate or its recipients can not be identified.
+func (ed *EnvelopedData) unwrapKeyAgree(ri []byte, cert *x509.Certificate, prv *gost3410.PrivateKey) ([]byte, error) {
+	var kari keyAgreeRecipientInfo
+	if _, err := asn1.Unmarshal(retag(ri, 0x30), &kari); err != nil {
+		return nil, nil
+	}
+	var wrapped []byte
+	for _, rek := range kari.RecipientEncryptedKeys {
+		rawIssuer, serial, ski, err := parseIdentifier(rek.RID)
+		if err == nil && identifies(cert, rawIssuer, serial, ski) {
+			wrapped = rek.EncryptedKey
+			break
+		}
+	}
+	if wrapped == nil {
+		return nil, nil
+	}
+	originator, err := unexplicit(kari.Originator, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(originator) == 0 || originator[0] != 0xA1 {
+		return nil, errors.New("Originator's public key expected")
+	}
+	eph, err := parseSPKI(retag(originator, 0x30))
+	if err != nil {
+		return nil, err
+	}
+	var wrapAI pkix.AlgorithmIdentifier
+	if _, err = asn1.Unmarshal(kari.KeyEncryptionAlgorithm.Parameters.FullBytes, &wrapAI); err != nil {
+		return nil, err
+	}
+	cc := *ed.cc
+	if oid.AlgorithmByOID(wrapAI.Algorithm) != cc.wrapAlgo {
+		return nil, errors.New("Unsupported key wrap algorithm")
+	}
+	ukm := kari.UKM
+	if cc.newCipher == nil {
+		var params keyWrapParams
+		if _, err = asn1.Unmarshal(wrapAI.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		if cc.sbox = oid.SboxByOID(params.EncryptionParamSet); cc.sbox == nil {
+			return nil, errors.New("Unknown S-box")
+		}
+		if ukm == nil {
+			ukm = params.UKM
+		}
+	}
+	return cc.unwrap(prv, eph, cert.PublicKeyAlgorithm, ukm, wrapped)
+}
+
+// Decrypt the content with the recipient's certificate and private key.
+// Recipient infos that can not be parsed or are for other recipients
+// are skipped.
+func (ed *EnvelopedData) Decrypt(cert *x509.Certificate, prv *gost3410.PrivateKey) ([]byte, error) {
+	for _, ri := range ed.recipientInfos {
+		var cek []byte
+		var err error
+		switch {
+		case ri.Class == asn1.ClassUniversal && ri.Tag == asn1.TagSequence:
+			cek, err = ed.unwrapKeyTrans(ri.FullBytes, cert, prv)
+		case ri.Class == asn1.ClassContextSpecific && ri.Tag == 1:
+			cek, err = ed.unwrapKeyAgree(ri.FullBytes, cert, prv)
+		default:
+			// Other recipient info types are skipped
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cek == nil {
+			continue
+		}
+		if len(cek) != acpkm.KeySize {
+			return nil, errors.New("Invalid content encryption key length")
+		}
+		content := make([]byte, len(ed.encryptedContent))
+		if err = ed.cc.scheme.Decrypt(cek, content, ed.encryptedContent); err != nil {
+			return nil, err
+		}
+		return content, nil
+	}
+	return nil, errors.New("No recipient info for the certificate")
+}
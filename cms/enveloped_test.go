@@ -0,0 +1,170 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+func TestEnvelopedDataSymmetric(t *testing.T) {
+	ids := newTestIdentities(t)
+	content := make([]byte, x509.MagmaSectionSize+123)
+	rand.Read(content)
+	for _, algo := range []oid.Algorithm{
+		oid.Gost2814789,
+		oid.KuznyechikCTRACPKM,
+		oid.MagmaCTRACPKM,
+	} {
+		recipients := ids
+		if algo != oid.Gost2814789 {
+			// KExp15 is not used with 34.10-2001 keys
+			recipients = ids[1:]
+		}
+		var certs []*x509.Certificate
+		for _, id := range recipients {
+			certs = append(certs, id.cert)
+		}
+		for _, keyAgreement := range []bool{false, true} {
+			der, err := Encrypt(rand.Reader, content, certs, &EncryptOptions{
+				Algorithm:    algo,
+				KeyAgreement: keyAgreement,
+			})
+			if err != nil {
+				t.FailNow()
+			}
+			ed, err := ParseEnvelopedData(der)
+			if err != nil ||
+				!ed.ContentType.Equal(oidData) ||
+				ed.ContentEncryptionAlgorithm != algo ||
+				len(ed.recipientInfos) != len(recipients) {
+				t.FailNow()
+			}
+			for _, id := range recipients {
+				decrypted, err := ed.Decrypt(id.cert, id.prv)
+				if err != nil || bytes.Compare(decrypted, content) != 0 {
+					t.FailNow()
+				}
+			}
+		}
+	}
+}
+
+func TestEnvelopedDataDefaults(t *testing.T) {
+	id := newTestIdentities(t)[0]
+	der, err := Encrypt(rand.Reader, []byte{}, []*x509.Certificate{id.cert}, nil)
+	if err != nil {
+		t.FailNow()
+	}
+	ed, err := ParseEnvelopedData(der)
+	if err != nil || ed.ContentEncryptionAlgorithm != oid.Gost2814789 {
+		t.FailNow()
+	}
+	decrypted, err := ed.Decrypt(id.cert, id.prv)
+	if err != nil || len(decrypted) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestEnvelopedDataWrongRecipient(t *testing.T) {
+	ids := newTestIdentities(t)
+	for _, algo := range []oid.Algorithm{oid.Gost2814789, oid.KuznyechikCTRACPKM} {
+		for _, keyAgreement := range []bool{false, true} {
+			der, err := Encrypt(rand.Reader, []byte("secret"), []*x509.Certificate{ids[1].cert}, &EncryptOptions{
+				Algorithm:    algo,
+				KeyAgreement: keyAgreement,
+			})
+			if err != nil {
+				t.FailNow()
+			}
+			ed, err := ParseEnvelopedData(der)
+			if err != nil {
+				t.FailNow()
+			}
+			if _, err = ed.Decrypt(ids[2].cert, ids[2].prv); err == nil {
+				t.FailNow()
+			}
+			// Same certificate, but another private key on the same curve
+			other := newTestIdentities(t)[1]
+			if _, err = ed.Decrypt(ids[1].cert, other.prv); err == nil {
+				t.FailNow()
+			}
+		}
+	}
+}
+
+func TestEnvelopedDataKExp152001(t *testing.T) {
+	id := newTestIdentities(t)[0]
+	if _, err := Encrypt(rand.Reader, []byte("secret"), []*x509.Certificate{id.cert}, &EncryptOptions{
+		Algorithm: oid.KuznyechikCTRACPKM,
+	}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEnvelopedDataUnsupportedAlgorithm(t *testing.T) {
+	id := newTestIdentities(t)[1]
+	if _, err := Encrypt(rand.Reader, []byte("secret"), []*x509.Certificate{id.cert}, &EncryptOptions{
+		Algorithm: oid.Gost2814789MAC,
+	}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEnvelopedDataSkipsBrokenRecipients(t *testing.T) {
+	ids := newTestIdentities(t)
+	var broken []asn1.RawValue
+	for _, der := range [][]byte{
+		{0x30, 0x03, 0x02, 0x01, 0x00}, // truncated KeyTransRecipientInfo
+		{0xA1, 0x02, 0x05, 0x00},       // invalid KeyAgreeRecipientInfo
+	} {
+		var ri asn1.RawValue
+		if _, err := asn1.Unmarshal(der, &ri); err != nil {
+			t.FailNow()
+		}
+		broken = append(broken, ri)
+	}
+	for _, keyAgreement := range []bool{false, true} {
+		der, err := Encrypt(rand.Reader, []byte("secret"), []*x509.Certificate{ids[1].cert, ids[2].cert}, &EncryptOptions{
+			Algorithm:    oid.KuznyechikCTRACPKM,
+			KeyAgreement: keyAgreement,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+		ed, err := ParseEnvelopedData(der)
+		if err != nil {
+			t.FailNow()
+		}
+		ed.recipientInfos = append(append([]asn1.RawValue{}, broken...), ed.recipientInfos...)
+		for _, id := range ids[1:3] {
+			decrypted, err := ed.Decrypt(id.cert, id.prv)
+			if err != nil || bytes.Compare(decrypted, []byte("secret")) != 0 {
+				t.FailNow()
+			}
+		}
+		if _, err = ed.Decrypt(ids[3].cert, ids[3].prv); err == nil {
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,428 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+var (
+	oidAttributeContentType          asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest        asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningTime          asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidAttributeSigningCertificateV2 asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+)
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     []asn1.RawValue `asn1:"optional,set,tag:0"`
+	CRLs             []asn1.RawValue `asn1:"optional,set,tag:1"`
+	SignerInfos      []signerInfo    `asn1:"set"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"optional"` // [0] EXPLICIT
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerSerial struct {
+	Issuer       []asn1.RawValue // GeneralNames
+	SerialNumber *big.Int
+}
+
+type essCertIDv2 struct {
+	HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"`
+	CertHash      []byte
+	IssuerSerial  issuerSerial `asn1:"optional"`
+}
+
+type signingCertificateV2 struct {
+	Certs    []essCertIDv2
+	Policies asn1.RawValue `asn1:"optional"`
+}
+
+// SignedData creation options.
+type SignOptions struct {
+	Detached     bool                // do not include the content
+	SigningTime  time.Time           // time.Now() if zero
+	Certificates []*x509.Certificate // additional ones, like intermediate CAs
+}
+
+// Signer of SignedData. Signer is identified either by issuer and
+// serial number, or by subject key identifier.
+type SignerInfo struct {
+	RawIssuer          []byte
+	SerialNumber       *big.Int
+	SubjectKeyId       []byte
+	DigestAlgorithm    oid.Algorithm
+	SignatureAlgorithm oid.Algorithm
+	Signature          []byte
+	SigningTime        time.Time // zero if absent
+
+	rawSignedAttrs []byte // DER encoded SET OF, nil if absent
+	signedAttrs    []attribute
+}
+
+// Parsed SignedData. Content is nil for detached signature.
+type SignedData struct {
+	ContentType  asn1.ObjectIdentifier
+	Content      []byte
+	Certificates []*x509.Certificate
+	Signers      []*SignerInfo
+}
+
+// Create SignedData with the single signer, wrapped in ContentInfo.
+// Digest algorithm is chosen by the certificate's public key algorithm:
+// GOST R 34.11-94 for 34.10-2001 and Streebog of corresponding size for
+// 34.10-2012. contentType, signingTime, messageDigest and
+// signingCertificateV2 signed attributes are included.
+func Sign(
+	rand io.Reader,
+	content []byte,
+	cert *x509.Certificate,
+	prv *gost3410.PrivateKey,
+	opts *SignOptions,
+) ([]byte, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if cert.PublicKey == nil || !cert.PublicKey.Equal(pub) {
+		return nil, errors.New("Private key does not match certificate")
+	}
+	digestAlgo := cert.PublicKeyAlgorithm.Digest()
+	digestAI := pkix.AlgorithmIdentifier{Algorithm: digestAlgo.OID()}
+	h := digestAlgo.NewHash()
+	if h == nil {
+		return nil, errors.New("Unsupported public key algorithm")
+	}
+	h.Write(content)
+	messageDigest := h.Sum(nil)
+	h = digestAlgo.NewHash()
+	h.Write(cert.Raw)
+	certHash := h.Sum(nil)
+	signingTime := opts.SigningTime
+	if signingTime.IsZero() {
+		signingTime = time.Now()
+	}
+
+	var attrs []attribute
+	for _, attr := range []struct {
+		typ asn1.ObjectIdentifier
+		v   interface{}
+	}{
+		{oidAttributeContentType, oidData},
+		{oidAttributeSigningTime, signingTime.UTC().Truncate(time.Second)},
+		{oidAttributeMessageDigest, messageDigest},
+		{oidAttributeSigningCertificateV2, signingCertificateV2{
+			Certs: []essCertIDv2{{
+				HashAlgorithm: digestAI,
+				CertHash:      certHash,
+				IssuerSerial: issuerSerial{
+					Issuer:       []asn1.RawValue{explicit(4, cert.RawIssuer)},
+					SerialNumber: cert.SerialNumber,
+				},
+			}},
+		}},
+	} {
+		a, err := newAttribute(attr.typ, attr.v)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, a)
+	}
+	signedAttrs, err := marshalAttributes(attrs)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := prv.SignMessageHash(digestAlgo.NewHash(), signedAttrs, rand)
+	if err != nil {
+		return nil, err
+	}
+	// SET OF is IMPLICIT [0] inside SignerInfo
+	signedAttrs[0] = 0xA0
+
+	sid, err := marshalIdentifier(cert)
+	if err != nil {
+		return nil, err
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAI},
+		EncapContentInfo: encapContentInfo{EContentType: oidData},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                sid,
+			DigestAlgorithm:    digestAI,
+			SignedAttrs:        asn1.RawValue{FullBytes: signedAttrs},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: cert.PublicKeyAlgorithm.OID()},
+			Signature:          signature,
+		}},
+	}
+	if !opts.Detached {
+		eContent, err := asn1.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		sd.EncapContentInfo.EContent = explicit(0, eContent)
+	}
+	for _, c := range append([]*x509.Certificate{cert}, opts.Certificates...) {
+		sd.Certificates = append(sd.Certificates, asn1.RawValue{FullBytes: c.Raw})
+	}
+	der, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+	return marshalContentInfo(oidSignedData, der)
+}
+
+func parseSignerInfo(si *signerInfo) (*SignerInfo, error) {
+	signer := SignerInfo{
+		DigestAlgorithm:    oid.AlgorithmByOID(si.DigestAlgorithm.Algorithm),
+		SignatureAlgorithm: oid.AlgorithmByOID(si.SignatureAlgorithm.Algorithm),
+		Signature:          si.Signature,
+	}
+	var err error
+	signer.RawIssuer, signer.SerialNumber, signer.SubjectKeyId, err = parseIdentifier(si.SID)
+	if err != nil {
+		return nil, err
+	}
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return &signer, nil
+	}
+	signer.rawSignedAttrs = append([]byte{}, si.SignedAttrs.FullBytes...)
+	signer.rawSignedAttrs[0] = 0x31
+	if _, err = asn1.UnmarshalWithParams(
+		signer.rawSignedAttrs, &signer.signedAttrs, "set",
+	); err != nil {
+		return nil, err
+	}
+	raw, err := findAttribute(signer.signedAttrs, oidAttributeSigningTime)
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		if _, err = asn1.Unmarshal(raw, &signer.SigningTime); err != nil {
+			return nil, err
+		}
+	}
+	return &signer, nil
+}
+
+// Parse DER encoded ContentInfo with SignedData.
+func ParseSignedData(der []byte) (*SignedData, error) {
+	raw, err := parseContentInfo(der, oidSignedData)
+	if err != nil {
+		return nil, err
+	}
+	var sd signedData
+	rest, err := asn1.Unmarshal(raw, &sd)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after signed data")
+	}
+	parsed := SignedData{ContentType: sd.EncapContentInfo.EContentType}
+	if len(sd.EncapContentInfo.EContent.FullBytes) > 0 {
+		eContent, err := unexplicit(sd.EncapContentInfo.EContent, 0)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = asn1.Unmarshal(eContent, &parsed.Content); err != nil {
+			return nil, err
+		}
+		if parsed.Content == nil {
+			parsed.Content = []byte{}
+		}
+	}
+	for _, rawCert := range sd.Certificates {
+		if rawCert.Class != asn1.ClassUniversal {
+			// Other certificate formats are skipped
+			continue
+		}
+		cert, err := x509.ParseCertificate(rawCert.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Certificates = append(parsed.Certificates, cert)
+	}
+	for i := range sd.SignerInfos {
+		signer, err := parseSignerInfo(&sd.SignerInfos[i])
+		if err != nil {
+			return nil, err
+		}
+		parsed.Signers = append(parsed.Signers, signer)
+	}
+	return &parsed, nil
+}
+
+// Signature algorithm corresponding to the digest one.
+func signatureAlgorithm(digestAlgo oid.Algorithm) oid.Algorithm {
+	switch digestAlgo {
+	case oid.GostR341194:
+		return oid.GostR341194WithGostR34102001
+	case oid.GostR34112012256:
+		return oid.GostR34102012256WithGostR34112012256
+	case oid.GostR34112012512:
+		return oid.GostR34102012512WithGostR34112012512
+	}
+	return oid.Unknown
+}
+
+func checkSigningCertificate(raw []byte, cert *x509.Certificate) error {
+	var sc signingCertificateV2
+	if _, err := asn1.Unmarshal(raw, &sc); err != nil {
+		return err
+	}
+	if len(sc.Certs) == 0 {
+		return errors.New("Empty signing certificate attribute")
+	}
+	var h hash.Hash
+	if sc.Certs[0].HashAlgorithm.Algorithm == nil {
+		h = sha256.New()
+	} else {
+		h = oid.AlgorithmByOID(sc.Certs[0].HashAlgorithm.Algorithm).NewHash()
+		if h == nil {
+			return errors.New("Unsupported signing certificate hash algorithm")
+		}
+	}
+	h.Write(cert.Raw)
+	if !bytes.Equal(h.Sum(nil), sc.Certs[0].CertHash) {
+		return errors.New("Signing certificate mismatch")
+	}
+	return nil
+}
+
+func (signer *SignerInfo) verify(contentType asn1.ObjectIdentifier, content []byte, cert *x509.Certificate) error {
+	if signer.SignatureAlgorithm.Digest() != signer.DigestAlgorithm {
+		return errors.New("Signature algorithm does not match digest one")
+	}
+	sigAlgo := signatureAlgorithm(signer.DigestAlgorithm)
+	if sigAlgo == oid.Unknown {
+		return errors.New("Unsupported digest algorithm")
+	}
+	if signer.rawSignedAttrs == nil {
+		return cert.CheckSignature(sigAlgo, content, signer.Signature)
+	}
+	raw, err := findAttribute(signer.signedAttrs, oidAttributeContentType)
+	if err != nil {
+		return err
+	}
+	var attrContentType asn1.ObjectIdentifier
+	if raw == nil {
+		return errors.New("No content type attribute")
+	}
+	if _, err = asn1.Unmarshal(raw, &attrContentType); err != nil {
+		return err
+	}
+	if !attrContentType.Equal(contentType) {
+		return errors.New("Content type mismatch")
+	}
+	if raw, err = findAttribute(signer.signedAttrs, oidAttributeMessageDigest); err != nil {
+		return err
+	}
+	if raw == nil {
+		return errors.New("No message digest attribute")
+	}
+	var messageDigest []byte
+	if _, err = asn1.Unmarshal(raw, &messageDigest); err != nil {
+		return err
+	}
+	h := signer.DigestAlgorithm.NewHash()
+	h.Write(content)
+	if !bytes.Equal(h.Sum(nil), messageDigest) {
+		return errors.New("Message digest mismatch")
+	}
+	if raw, err = findAttribute(signer.signedAttrs, oidAttributeSigningCertificateV2); err != nil {
+		return err
+	}
+	if raw != nil {
+		if err = checkSigningCertificate(raw, cert); err != nil {
+			return err
+		}
+	}
+	return cert.CheckSignature(sigAlgo, signer.rawSignedAttrs, signer.Signature)
+}
+
+// Verify signatures of all signers. Detached content must be given if
+// it is not encapsulated. Signers' certificates are searched among the
+// included and given ones, and returned in the order of signers.
+// Certificates are not verified themselves: use
+// x509.Certificate.Verify with SignedData's certificates as
+// intermediates.
+func (sd *SignedData) Verify(content []byte, certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	if sd.Content != nil {
+		if content != nil && !bytes.Equal(content, sd.Content) {
+			return nil, errors.New("Content mismatch")
+		}
+		content = sd.Content
+	}
+	if content == nil {
+		return nil, errors.New("No content for detached signature")
+	}
+	if len(sd.Signers) == 0 {
+		return nil, errors.New("No signers")
+	}
+	certs = append(append([]*x509.Certificate{}, sd.Certificates...), certs...)
+	signerCerts := make([]*x509.Certificate, 0, len(sd.Signers))
+	for _, signer := range sd.Signers {
+		var cert *x509.Certificate
+		for _, c := range certs {
+			if identifies(c, signer.RawIssuer, signer.SerialNumber, signer.SubjectKeyId) {
+				cert = c
+				break
+			}
+		}
+		if cert == nil {
+			return nil, errors.New("Signer's certificate not found")
+		}
+		if err := signer.verify(sd.ContentType, content, cert); err != nil {
+			return nil, err
+		}
+		signerCerts = append(signerCerts, cert)
+	}
+	return signerCerts, nil
+}
@@ -0,0 +1,297 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+var (
+	testNotBefore time.Time = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testNotAfter  time.Time = time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC)
+	testNow       time.Time = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Serial numbers must be unique among certificates of the same
+	// issuer, otherwise issuerAndSerialNumber is ambiguous
+	testSerial int64
+)
+
+type testIdentity struct {
+	prv  *gost3410.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestIdentity(
+	t *testing.T,
+	params *gost3410.CurveParams,
+	mode gost3410.Mode,
+	algo oid.Algorithm,
+	cn string,
+	issuer *testIdentity,
+) *testIdentity {
+	c, err := gost3410.NewCurveFromParams(*params)
+	if err != nil {
+		t.FailNow()
+	}
+	prv, err := gost3410.GenPrivateKey(c, mode, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	pub, _ := prv.PublicKey()
+	testSerial++
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             testNotBefore,
+		NotAfter:              testNotAfter,
+		BasicConstraintsValid: issuer == nil,
+		IsCA:                  issuer == nil,
+		MaxPathLen:            -1,
+		PublicKeyAlgorithm:    algo,
+	}
+	parent, signer := template, prv
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.prv
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		t.FailNow()
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.FailNow()
+	}
+	return &testIdentity{prv, cert}
+}
+
+func newTestIdentities(t *testing.T) []*testIdentity {
+	ca := newTestIdentity(
+		t, &gost3410.CurveParamsGostR34102012TC26ParamSetA, gost3410.Mode2012,
+		oid.GostR34102012512, "CA", nil,
+	)
+	return []*testIdentity{
+		newTestIdentity(
+			t, &gost3410.CurveParamsGostR34102001CryptoProA, gost3410.Mode2001,
+			oid.GostR34102001, "Signer 2001", ca,
+		),
+		newTestIdentity(
+			t, &gost3410.CurveParamsGostR34102012TC26ParamSetA256, gost3410.Mode2001,
+			oid.GostR34102012256, "Signer 2012-256", ca,
+		),
+		newTestIdentity(
+			t, &gost3410.CurveParamsGostR34102012TC26ParamSetC, gost3410.Mode2012,
+			oid.GostR34102012512, "Signer 2012-512", ca,
+		),
+		ca,
+	}
+}
+
+func TestSignedDataSymmetric(t *testing.T) {
+	content := []byte("some document")
+	signingTime := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	for _, id := range newTestIdentities(t) {
+		der, err := Sign(rand.Reader, content, id.cert, id.prv, &SignOptions{
+			SigningTime: signingTime,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+		sd, err := ParseSignedData(der)
+		if err != nil ||
+			!sd.ContentType.Equal(oidData) ||
+			bytes.Compare(sd.Content, content) != 0 ||
+			len(sd.Certificates) != 1 ||
+			!sd.Certificates[0].Equal(id.cert) ||
+			len(sd.Signers) != 1 {
+			t.FailNow()
+		}
+		signer := sd.Signers[0]
+		if signer.DigestAlgorithm != id.cert.PublicKeyAlgorithm.Digest() ||
+			signer.SignatureAlgorithm != id.cert.PublicKeyAlgorithm ||
+			!signer.SigningTime.Equal(signingTime) ||
+			len(signer.signedAttrs) != 4 {
+			t.FailNow()
+		}
+		certs, err := sd.Verify(nil, nil)
+		if err != nil || len(certs) != 1 || !certs[0].Equal(id.cert) {
+			t.FailNow()
+		}
+		if _, err = sd.Verify([]byte("other document"), nil); err == nil {
+			t.FailNow()
+		}
+		sd.Content[0] ^= 0x01
+		if _, err = sd.Verify(nil, nil); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignedDataDetached(t *testing.T) {
+	content := []byte("some document")
+	id := newTestIdentities(t)[1]
+	der, err := Sign(rand.Reader, content, id.cert, id.prv, &SignOptions{Detached: true})
+	if err != nil {
+		t.FailNow()
+	}
+	sd, err := ParseSignedData(der)
+	if err != nil || sd.Content != nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify(nil, nil); err == nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify(content, nil); err != nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify([]byte("other document"), nil); err == nil {
+		t.FailNow()
+	}
+	der, err = Sign(rand.Reader, []byte{}, id.cert, id.prv, &SignOptions{Detached: true})
+	if err != nil {
+		t.FailNow()
+	}
+	if sd, err = ParseSignedData(der); err != nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify([]byte{}, nil); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestSignedDataChain(t *testing.T) {
+	ids := newTestIdentities(t)
+	ca := ids[len(ids)-1]
+	der, err := Sign(rand.Reader, []byte("data"), ids[0].cert, ids[0].prv, &SignOptions{
+		Certificates: []*x509.Certificate{ca.cert},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	sd, err := ParseSignedData(der)
+	if err != nil || len(sd.Certificates) != 2 {
+		t.FailNow()
+	}
+	certs, err := sd.Verify(nil, nil)
+	if err != nil {
+		t.FailNow()
+	}
+	chain, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         []*x509.Certificate{ca.cert},
+		Intermediates: sd.Certificates,
+		CurrentTime:   testNow,
+	})
+	if err != nil || len(chain) != 2 {
+		t.FailNow()
+	}
+}
+
+func TestSignedDataExternalCertificate(t *testing.T) {
+	ids := newTestIdentities(t)
+	der, err := Sign(rand.Reader, []byte("data"), ids[2].cert, ids[2].prv, nil)
+	if err != nil {
+		t.FailNow()
+	}
+	sd, err := ParseSignedData(der)
+	if err != nil {
+		t.FailNow()
+	}
+	sd.Certificates = nil
+	if _, err = sd.Verify(nil, nil); err == nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify(nil, []*x509.Certificate{ids[0].cert, ids[2].cert}); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestSignedDataSignerSelection(t *testing.T) {
+	ids := newTestIdentities(t)
+	var certs []*x509.Certificate
+	for _, id := range ids {
+		for _, c := range certs {
+			if c.SerialNumber.Cmp(id.cert.SerialNumber) == 0 {
+				t.FailNow()
+			}
+		}
+		certs = append(certs, id.cert)
+	}
+	for _, id := range ids[:3] {
+		der, err := Sign(rand.Reader, []byte("data"), id.cert, id.prv, nil)
+		if err != nil {
+			t.FailNow()
+		}
+		sd, err := ParseSignedData(der)
+		if err != nil {
+			t.FailNow()
+		}
+		sd.Certificates = nil
+		signers, err := sd.Verify(nil, certs)
+		if err != nil || len(signers) != 1 || !signers[0].Equal(id.cert) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignedDataWrongKey(t *testing.T) {
+	ids := newTestIdentities(t)
+	if _, err := Sign(rand.Reader, []byte("data"), ids[0].cert, ids[1].prv, nil); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestSignedDataSigningCertificate(t *testing.T) {
+	ids := newTestIdentities(t)
+	der, err := Sign(rand.Reader, []byte("data"), ids[1].cert, ids[1].prv, nil)
+	if err != nil {
+		t.FailNow()
+	}
+	sd, err := ParseSignedData(der)
+	if err != nil {
+		t.FailNow()
+	}
+	// Certificate with the same issuer, serial and key, but another
+	// validity period
+	template := *ids[1].cert
+	template.NotAfter = testNow
+	certDer, err := x509.CreateCertificate(
+		rand.Reader, &template, ids[3].cert, ids[1].cert.PublicKey, ids[3].prv,
+	)
+	if err != nil {
+		t.FailNow()
+	}
+	other, err := x509.ParseCertificate(certDer)
+	if err != nil {
+		t.FailNow()
+	}
+	if _, err = sd.Verify(nil, []*x509.Certificate{other}); err != nil {
+		t.FailNow()
+	}
+	sd.Certificates = nil
+	if _, err = sd.Verify(nil, []*x509.Certificate{other}); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,258 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package cms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+// SignedData below is DER encoded by hand and signed with GNU Nettle 3.8
+// gostdsa_sign over its own Streebog implementation. It has two
+// signers, in DER SET OF order: 34.10-2012 512-bit CA key without
+// signed attributes, and 256-bit key issued by it with contentType,
+// signingTime and messageDigest signed attributes. Both certificates
+// are included.
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var (
+	nettleContent    []byte = []byte("Signed by GNU Nettle\n")
+	nettleSignedData []byte = hexDecode("" +
+		"3082065a06092a864886f70d010702a082064b308206470201013118300a0608" +
+		"2a85030701010202300a06082a85030701010203302406092a864886f70d0107" +
+		"01a01704155369676e656420627920474e55204e6574746c650aa082040d3082" +
+		"01e930820155a00302010202024a1c300a06082a850307010103033039310b30" +
+		"09060355040613025255310f300d060355040a0c06476f474f53543119301706" +
+		"035504030c10476f474f5354204e6574746c65204341301e170d323030313031" +
+		"3030303030305a170d3339313233313233353935395a303b310b300906035504" +
+		"0613025255310f300d060355040a0c06476f474f5354311b301906035504030c" +
+		"12476f474f5354204e6574746c65204c6561663066301f06082a850307010101" +
+		"01301306072a85030202230106082a8503070101020203430004406ee214d54d" +
+		"40ec2dd55622be9d26559aa9203792d9437e268e61512513e60f40508f5338c3" +
+		"9099791fe66e69510091073f0ad302bae77db7ed93e2e7acfd8d53a33e303c30" +
+		"0e0603551d0f0101ff04040302078030130603551d0e040c040a112233445566" +
+		"7788990030150603551d23040e300c800a7f3a1c5e9b2d4f6a8c0e300a06082a" +
+		"850307010103030381810054e4615761cd157fd21cc8d65ab82b5fbfc012c577" +
+		"612ceaeb51741ee19b8ba6c258f37771ad484efd610a725b622f61391d79c2ce" +
+		"54b8208b586700070e562a75422c6debbccff398b575f5fc0bb4e5805fcc5515" +
+		"af6eae2a3ad96c13387b4a2c71b7abc9c7cbef2a3edc280844e3f7642b005cc6" +
+		"99b03caa552461b7635ce93082021c30820188a00302010202024a1b300a0608" +
+		"2a850307010103033039310b3009060355040613025255310f300d060355040a" +
+		"0c06476f474f53543119301706035504030c10476f474f5354204e6574746c65" +
+		"204341301e170d3230303130313030303030305a170d33393132333132333539" +
+		"35395a3039310b3009060355040613025255310f300d060355040a0c06476f47" +
+		"4f53543119301706035504030c10476f474f5354204e6574746c652043413081" +
+		"a0301706082a85030701010102300b06092a8503070102010201038184000481" +
+		"8098ec54e2f3ff78ab7bead6364517585ce4a49f7e8a779780bb18370c01e016" +
+		"3a53de29ca076371450babf42f8d79d6371080d7a0e7ecfc592461c4156c1330" +
+		"fe0d9c1e7e5f980a5dc98be063122c02437441d762c890fa97175afd86190e78" +
+		"4fa1fcc9d440fe1e93ca376994bacb030e1fc1329d7b1570435f12e4fe4a18e8" +
+		"79a3383036300f0603551d130101ff040530030101ff300e0603551d0f0101ff" +
+		"04040302010630130603551d0e040c040a7f3a1c5e9b2d4f6a8c0e300a06082a" +
+		"85030701010303038181007784909ab4b057807c01528628584b7f3c54721509" +
+		"31a04742dfd0a65f0e82326e961b14f2a11d6c6a5ceda3ea72d74a7a8c5b0e29" +
+		"86e3d67920714f36fc053b5b76286c139fcca32955da8231b411a0ee3ae11bfa" +
+		"86d77b017e8eed0567321da7c2da4a4cde8acc94161b293bc0e436efa75242fd" +
+		"b1e8a8555cd92ae93b2d04318201ef3081df020101303f3039310b3009060355" +
+		"040613025255310f300d060355040a0c06476f474f5354311930170603550403" +
+		"0c10476f474f5354204e6574746c6520434102024a1b300a06082a8503070101" +
+		"0203300a06082a85030701010102048180601ab02bce5bc33efb70307c0ce64d" +
+		"016446fb4a8c23fcf26fd3e17c761ea7fed06ce84163a3873205e59fbb91fe0a" +
+		"23efc6b0e8bd9ace26ac3f78e7ddc1333ca7d13a3ec3c44ca3ab6b08ac358ace" +
+		"1ea0298d8ddb6642b4dff203ee4ce184f68376c293b806df125e7bc698fc8e4e" +
+		"77d6d646fb63a0a2fa72966353e9155d4030820109020101303f3039310b3009" +
+		"060355040613025255310f300d060355040a0c06476f474f5354311930170603" +
+		"5504030c10476f474f5354204e6574746c6520434102024a1c300a06082a8503" +
+		"0701010202a069301806092a864886f70d010903310b06092a864886f70d0107" +
+		"01301c06092a864886f70d010905310f170d3235303330343035303630375a30" +
+		"2f06092a864886f70d01090431220420741d78f3e11b46213484a65cec7a1f5c" +
+		"c7a2d0d8240f59f4a704e5986d796da1300a06082a85030701010101044071f7" +
+		"ae0cd5cad3f6289ee7388870a172637813aefd2d5df7e5577284898d376df333" +
+		"f6a64d6719dc07ad97146e44010b20d27beca3a321417c9432b1ab91007d",
+	)
+)
+
+// KExp15 keys for the recipient's and ephemeral 34.10-2012 256-bit keys
+// on CryptoPro-A curve, derived with Nettle's gostdsa_vko,
+// streebog256 and HMAC-Streebog-256 based KDF_TREE.
+var (
+	kexp15RecipientPrv []byte = hexDecode("" +
+		"102d3e5f7a9b1c3d0e8f5a7b2c4e6d9a3f1b8c5e7d2a0f6b4c9e3d8a5f2b7e1c",
+	)
+	kexp15RecipientPub []byte = hexDecode("" +
+		"0341d29747aafb11b51b4324fb1d92ad05741b416e3342fa8768c63cce540233" +
+		"4f6f611f990945408374eb84475fd0cbcf3d1c19dd9fb4b0b91c7f1abbfa1b25",
+	)
+	kexp15EphemeralPrv []byte = hexDecode("" +
+		"402f9d7b5e3c1a0f8d6b4e2c0a9f7d5b3e1c8a6f4d2b0e8c6a4f2d1b9f7e5c3a",
+	)
+	kexp15EphemeralPub []byte = hexDecode("" +
+		"d2597c98e83050004b948ec348d440411d7e01f45b65521985527e5a83918afb" +
+		"c6334feb3251cd5594a97546acee6e41fb230cd0c8fb30fcb1798651922faeaf",
+	)
+	kexp15UKM []byte = hexDecode("" +
+		"a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf",
+	)
+	kexp15MACKey []byte = hexDecode("" +
+		"6572b7fc1dc9bbe010b7c70bdf8728d76ef6509ad84a9925aa727f236a36374a",
+	)
+	kexp15EncKey []byte = hexDecode("" +
+		"85befccf3fac49a53de0002803ea4e87e63b6f220e41e41ea50e60ab87dc2d47",
+	)
+)
+
+func TestNettleKExp15Keys(t *testing.T) {
+	c, err := gost3410.NewCurveFromParams(gost3410.CurveParamsGostR34102001CryptoProA)
+	if err != nil {
+		t.FailNow()
+	}
+	for _, pair := range [][2][]byte{
+		{kexp15EphemeralPrv, kexp15RecipientPub},
+		{kexp15RecipientPrv, kexp15EphemeralPub},
+	} {
+		prv, err := gost3410.NewPrivateKey(c, gost3410.Mode2001, pair[0])
+		if err != nil {
+			t.FailNow()
+		}
+		pub, err := gost3410.NewPublicKey(c, gost3410.Mode2001, pair[1])
+		if err != nil {
+			t.FailNow()
+		}
+		macKey, encKey, err := kexp15Keys(prv, pub, kexp15UKM)
+		if err != nil ||
+			bytes.Compare(macKey, kexp15MACKey) != 0 ||
+			bytes.Compare(encKey, kexp15EncKey) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestNettleSignedData(t *testing.T) {
+	sd, err := ParseSignedData(nettleSignedData)
+	if err != nil ||
+		!sd.ContentType.Equal(oidData) ||
+		bytes.Compare(sd.Content, nettleContent) != 0 ||
+		len(sd.Certificates) != 2 ||
+		len(sd.Signers) != 2 {
+		t.FailNow()
+	}
+	ca, leaf := sd.Signers[0], sd.Signers[1]
+	if leaf.DigestAlgorithm != oid.GostR34112012256 ||
+		leaf.SignatureAlgorithm != oid.GostR34102012256 ||
+		!leaf.SigningTime.Equal(time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)) ||
+		ca.DigestAlgorithm != oid.GostR34112012512 ||
+		ca.SignatureAlgorithm != oid.GostR34102012512 ||
+		!ca.SigningTime.IsZero() {
+		t.FailNow()
+	}
+	certs, err := sd.Verify(nil, nil)
+	if err != nil ||
+		len(certs) != 2 ||
+		certs[0].Subject.CommonName != "GoGOST Nettle CA" ||
+		certs[1].Subject.CommonName != "GoGOST Nettle Leaf" {
+		t.FailNow()
+	}
+	chain, err := certs[1].Verify(x509.VerifyOptions{
+		Roots:       []*x509.Certificate{certs[0]},
+		CurrentTime: testNow,
+	})
+	if err != nil || len(chain) != 2 {
+		t.FailNow()
+	}
+	if _, err = sd.Verify([]byte("Signed by someone else\n"), nil); err == nil {
+		t.FailNow()
+	}
+	sd.Content[0] ^= 0x01
+	if _, err = sd.Verify(nil, nil); err == nil {
+		t.FailNow()
+	}
+}
+
+// EnvelopedData below is DER encoded by hand after RFC 4490: KeyTrans
+// to the Nettle leaf certificate above, CEK wrapped with CryptoPro key
+// wrap and content encrypted in CFB mode, both with
+// id-tc26-gost-28147-param-Z S-box. KEK is computed with Nettle 3.8.1
+// gostdsa_vko and streebog256, key wrap and encryption are done with
+// GnuTLS 3.7.9 28147-89 implementation.
+var (
+	nettleLeafPrv []byte = hexDecode("" +
+		"8798a9b0c1d2e3f45feed67dccb89baa807162534435261788796a5b4c3d1e2f",
+	)
+	nettleEnvelopedContent []byte = []byte("Enveloped with GNU Nettle and GnuTLS\n")
+	nettleEnvelopedData    []byte = hexDecode("" +
+		"3082017106092a864886f70d010703a08201623082015e020100318201023081" +
+		"ff020100303f3039310b3009060355040613025255310f300d060355040a0c06" +
+		"476f474f53543119301706035504030c10476f474f5354204e6574746c652043" +
+		"4102024a1c300a06082a850307010101010481ac3081a9302804200a907c8907" +
+		"653e1fc5574fce34cf7c998e49b361c1d98012b5ec9807a527133804046f41fe" +
+		"bba07d06092a8503070102050101a066301f06082a8503070101010130130607" +
+		"2a85030202230106082a85030701010202034300044010fbc3128558731cc625" +
+		"f6a781b5c6174cc6372fdc01211f41aa8245046d26f6f80761579a3aaf86237c" +
+		"281295d176529376db98511054aa0555c8a9da196eba04080a1b2c3d4e5f6071" +
+		"305306092a864886f70d010701301f06062a8503020215301504081122334455" +
+		"66778806092a85030701020501018025bfe6e0c322654f766dea765b5df63cc6" +
+		"73389246ab1d1ebc7d77001d87c14c69325260d384",
+	)
+)
+
+func TestNettleEnvelopedData(t *testing.T) {
+	sd, err := ParseSignedData(nettleSignedData)
+	if err != nil {
+		t.FailNow()
+	}
+	var leaf *x509.Certificate
+	for _, cert := range sd.Certificates {
+		if cert.Subject.CommonName == "GoGOST Nettle Leaf" {
+			leaf = cert
+		}
+	}
+	if leaf == nil {
+		t.FailNow()
+	}
+	prv, err := gost3410.NewPrivateKey(leaf.PublicKey.Curve(), gost3410.Mode2001, nettleLeafPrv)
+	if err != nil {
+		t.FailNow()
+	}
+	pub, _ := prv.PublicKey()
+	if !pub.Equal(leaf.PublicKey) {
+		t.FailNow()
+	}
+	ed, err := ParseEnvelopedData(nettleEnvelopedData)
+	if err != nil ||
+		!ed.ContentType.Equal(oidData) ||
+		ed.ContentEncryptionAlgorithm != oid.Gost2814789 {
+		t.FailNow()
+	}
+	decrypted, err := ed.Decrypt(leaf, prv)
+	if err != nil || bytes.Compare(decrypted, nettleEnvelopedContent) != 0 {
+		t.FailNow()
+	}
+}
@@ -18,55 +18,83 @@
 package gost28147
 
 type CFBEncrypter struct {
-	c  *Cipher
-	iv []byte
+	c       *Cipher
+	iv      []byte
+	used    int
+	meshing bool
+	count   int
 }
 
 func (c *Cipher) NewCFBEncrypter(iv [BlockSize]byte) *CFBEncrypter {
-	return &CFBEncrypter{c, iv[:]}
+	return &CFBEncrypter{c: c, iv: iv[:], used: BlockSize}
 }
 
+// CFB encrypter with CryptoPro key meshing (RFC 4357 2.3): key and IV
+// are changed after every MeshBlockSize bytes of data.
+func (c *Cipher) NewCFBEncrypterMesh(iv [BlockSize]byte) *CFBEncrypter {
+	return &CFBEncrypter{c: c, iv: iv[:], used: BlockSize, meshing: true}
+}
+
+// Encrypt the feedback register, producing the next gamma block.
+func (c *CFBEncrypter) next() {
+	if c.meshing && c.count == MeshBlockSize {
+		c.c = c.c.meshCryptoPro(c.iv)
+		c.count = 0
+	}
+	c.c.Encrypt(c.iv, c.iv)
+	c.count += BlockSize
+	c.used = 0
+}
+
+// Data may be fed in arbitrary sized chunks: unused gamma of the
+// partially processed block is kept for the next call.
 func (c *CFBEncrypter) XORKeyStream(dst, src []byte) {
-	var n int
-	i := 0
-MainLoop:
-	for {
-		c.c.Encrypt(c.iv, c.iv)
-		for n = 0; n < BlockSize; n++ {
-			if i*BlockSize+n == len(src) {
-				break MainLoop
-			}
-			c.iv[n] ^= src[i*BlockSize+n]
-			dst[i*BlockSize+n] = c.iv[n]
+	for i := 0; i < len(src); i++ {
+		if c.used == BlockSize {
+			c.next()
 		}
-		i++
+		c.iv[c.used] ^= src[i]
+		dst[i] = c.iv[c.used]
+		c.used++
 	}
-	return
 }
 
 type CFBDecrypter struct {
-	c  *Cipher
-	iv []byte
+	c       *Cipher
+	iv      []byte
+	used    int
+	meshing bool
+	count   int
 }
 
 func (c *Cipher) NewCFBDecrypter(iv [BlockSize]byte) *CFBDecrypter {
-	return &CFBDecrypter{c, iv[:]}
+	return &CFBDecrypter{c: c, iv: iv[:], used: BlockSize}
+}
+
+// CFB decrypter with CryptoPro key meshing (RFC 4357 2.3).
+func (c *Cipher) NewCFBDecrypterMesh(iv [BlockSize]byte) *CFBDecrypter {
+	return &CFBDecrypter{c: c, iv: iv[:], used: BlockSize, meshing: true}
+}
+
+func (c *CFBDecrypter) next() {
+	if c.meshing && c.count == MeshBlockSize {
+		c.c = c.c.meshCryptoPro(c.iv)
+		c.count = 0
+	}
+	c.c.Encrypt(c.iv, c.iv)
+	c.count += BlockSize
+	c.used = 0
 }
 
 func (c *CFBDecrypter) XORKeyStream(dst, src []byte) {
-	var n int
-	i := 0
-MainLoop:
-	for {
-		c.c.Encrypt(c.iv, c.iv)
-		for n = 0; n < BlockSize; n++ {
-			if i*BlockSize+n == len(src) {
-				break MainLoop
-			}
-			dst[i*BlockSize+n] = c.iv[n] ^ src[i*BlockSize+n]
-			c.iv[n] = src[i*BlockSize+n]
+	var b byte
+	for i := 0; i < len(src); i++ {
+		if c.used == BlockSize {
+			c.next()
 		}
-		i++
+		b = src[i]
+		dst[i] = c.iv[c.used] ^ b
+		c.iv[c.used] = b
+		c.used++
 	}
-	return
 }
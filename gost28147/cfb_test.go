@@ -56,6 +56,31 @@ func TestCFBCryptomanager(t *testing.T) {
 	}
 }
 
+// Gamma is the same however data is split between calls
+func TestCFBChunks(t *testing.T) {
+	key, iv, pt := meshTestData()
+	c := NewCipher(key, SboxDefault)
+	ct := make([]byte, len(pt))
+	c.NewCFBEncrypter(iv).XORKeyStream(ct, pt)
+	for _, size := range []int{1, 3, BlockSize, 2*BlockSize + 1} {
+		fe := c.NewCFBEncrypter(iv)
+		fd := c.NewCFBDecrypter(iv)
+		ct2 := make([]byte, len(pt))
+		pt2 := make([]byte, len(pt))
+		for i := 0; i < len(pt); i += size {
+			end := i + size
+			if end > len(pt) {
+				end = len(pt)
+			}
+			fe.XORKeyStream(ct2[i:end], pt[i:end])
+			fd.XORKeyStream(pt2[i:end], ct2[i:end])
+		}
+		if bytes.Compare(ct2, ct) != 0 || bytes.Compare(pt2, pt) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
 func TestCFBRandom(t *testing.T) {
 	var key [KeySize]byte
 	rand.Read(key[:])
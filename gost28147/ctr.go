@@ -18,39 +18,58 @@
 package gost28147
 
 type CTR struct {
-	c  *Cipher
-	n1 nv
-	n2 nv
+	c       *Cipher
+	n1      nv
+	n2      nv
+	block   []byte
+	used    int
+	meshing bool
+	count   int
 }
 
 func (c *Cipher) NewCTR(iv [BlockSize]byte) *CTR {
 	n1, n2 := block2nvs(iv[:])
 	n2, n1 = c.xcrypt(SeqEncrypt, n1, n2)
-	return &CTR{c, n1, n2}
+	return &CTR{c: c, n1: n1, n2: n2, block: make([]byte, BlockSize), used: BlockSize}
 }
 
+// CTR with CryptoPro key meshing (RFC 4357 2.3): key and counter are
+// changed after every MeshBlockSize bytes of data.
+func (c *Cipher) NewCTRMesh(iv [BlockSize]byte) *CTR {
+	ctr := c.NewCTR(iv)
+	ctr.meshing = true
+	return ctr
+}
+
+// Increment the counter and encrypt it, producing the next gamma block.
+func (c *CTR) next() {
+	if c.meshing && c.count == MeshBlockSize {
+		nvs2block(c.n2, c.n1, c.block)
+		c.c = c.c.meshCryptoPro(c.block)
+		c.n1, c.n2 = block2nvs(c.block)
+		c.count = 0
+	}
+	c.n1 += 0x01010101 // C2
+	// C1 is added modulo 2^32-1 as GOST 28147-89 defines it: carry
+	// is added back
+	c.n2 += 0x01010104
+	if c.n2 < 0x01010104 {
+		c.n2++
+	}
+	n1t, n2t := c.c.xcrypt(SeqEncrypt, c.n1, c.n2)
+	nvs2block(n1t, n2t, c.block)
+	c.count += BlockSize
+	c.used = 0
+}
+
+// Data may be fed in arbitrary sized chunks: unused gamma of the
+// partially processed block is kept for the next call.
 func (c *CTR) XORKeyStream(dst, src []byte) {
-	var n1t nv
-	var n2t nv
-	block := make([]byte, BlockSize)
-	i := 0
-	var n int
-MainLoop:
-	for {
-		c.n1 += 0x01010101 // C2
-		c.n2 += 0x01010104 // C1
-		if c.n2 >= 1<<32-1 {
-			c.n2 -= 1<<32 - 1
-		}
-		n1t, n2t = c.c.xcrypt(SeqEncrypt, c.n1, c.n2)
-		nvs2block(n1t, n2t, block)
-		for n = 0; n < BlockSize; n++ {
-			if i*BlockSize+n == len(src) {
-				break MainLoop
-			}
-			dst[i*BlockSize+n] = src[i*BlockSize+n] ^ block[n]
+	for i := 0; i < len(src); i++ {
+		if c.used == BlockSize {
+			c.next()
 		}
-		i++
+		dst[i] = src[i] ^ c.block[c.used]
+		c.used++
 	}
-	return
 }
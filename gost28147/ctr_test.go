@@ -157,6 +157,39 @@ func TestCTRGCL2Vector(t *testing.T) {
 	}
 }
 
+// N2 counter overflows several times here, so that checks addition
+// modulo 2^32-1. Expected SHA-256 of ciphertext is computed with
+// GnuTLS 3.7.9 and its test parameters without key meshing.
+func TestCTRCounterOverflow(t *testing.T) {
+	key, iv, pt := meshTestData()
+	c := NewCipher(key, &Gost2814789_TestParamSet)
+	ct := make([]byte, len(pt))
+	c.NewCTR(iv).XORKeyStream(ct, pt)
+	testSHA256(t, ct, "a7efef0ccf87e02a1d56ef09aac5e66696797dfab0118f9cdeabf39173b19002")
+}
+
+// Gamma is the same however data is split between calls
+func TestCTRChunks(t *testing.T) {
+	key, iv, pt := meshTestData()
+	c := NewCipher(key, SboxDefault)
+	ct := make([]byte, len(pt))
+	c.NewCTR(iv).XORKeyStream(ct, pt)
+	for _, size := range []int{1, 3, BlockSize, 2*BlockSize + 1} {
+		ctr := c.NewCTR(iv)
+		ct2 := make([]byte, len(pt))
+		for i := 0; i < len(pt); i += size {
+			end := i + size
+			if end > len(pt) {
+				end = len(pt)
+			}
+			ctr.XORKeyStream(ct2[i:end], pt[i:end])
+		}
+		if bytes.Compare(ct2, ct) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
 func TestCTRRandom(t *testing.T) {
 	var key [KeySize]byte
 	rand.Read(key[:])
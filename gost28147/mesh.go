@@ -0,0 +1,45 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost28147
+
+const (
+	// Amount of data processed with the same key before CryptoPro key
+	// meshing happens.
+	MeshBlockSize = 1024
+)
+
+var (
+	// CryptoPro key meshing constant C. RFC 4357 2.3.1.
+	CryptoProMeshingC = [KeySize]byte{
+		0x69, 0x00, 0x72, 0x22, 0x64, 0xC9, 0x04, 0x23,
+		0x8D, 0x3A, 0xDB, 0x96, 0x46, 0xE9, 0x2A, 0xC4,
+		0x18, 0xFE, 0xAC, 0x94, 0x00, 0xED, 0x07, 0x12,
+		0xC0, 0x86, 0xDC, 0xC2, 0xEF, 0x4C, 0xA9, 0x2B,
+	}
+)
+
+// CryptoPro key meshing (RFC 4357 2.3): new key is decryption of C
+// constant with the current key, new IV is encryption of the current
+// one with the new key. IV is updated in place.
+func (c *Cipher) meshCryptoPro(iv []byte) *Cipher {
+	var key [KeySize]byte
+	c.NewECBDecrypter().CryptBlocks(key[:], CryptoProMeshingC[:])
+	meshed := NewCipher(key, c.sbox)
+	meshed.Encrypt(iv, iv)
+	return meshed
+}
@@ -0,0 +1,162 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost28147
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// Key, IV and 3077-byte 00 01 02 ... plaintext of known-answer tests
+// crossing two meshing boundaries. Expected SHA-256 of ciphertexts are
+// computed with GnuTLS 3.7.9 28147-89 implementation and CryptoPro-A
+// parameters, that have key meshing enabled.
+func meshTestData() (key [KeySize]byte, iv [BlockSize]byte, pt []byte) {
+	copy(key[:], hexDecode(""+
+		"75713134b60fec45a607bb83aa3746af"+
+		"4ff99da6d1b53b5b1b402a1baa030d1b",
+	))
+	copy(iv[:], hexDecode("0102030405060708"))
+	pt = make([]byte, 3*MeshBlockSize+5)
+	for i := 0; i < len(pt); i++ {
+		pt[i] = byte(i)
+	}
+	return
+}
+
+func testSHA256(t *testing.T, data []byte, expected string) {
+	sum := sha256.Sum256(data)
+	if bytes.Compare(sum[:], hexDecode(expected)) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCFBMeshGnuTLS(t *testing.T) {
+	key, iv, pt := meshTestData()
+	c := NewCipher(key, &Gost28147_CryptoProParamSetA)
+	ct := make([]byte, len(pt))
+	c.NewCFBEncrypterMesh(iv).XORKeyStream(ct, pt)
+	testSHA256(t, ct, "2a5d822ffddb259ab900dbbc79b6d5e9aae0a7b5b41bdc58efef33ba7196e0f6")
+	c.NewCFBDecrypterMesh(iv).XORKeyStream(ct, ct)
+	if bytes.Compare(ct, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCTRMeshGnuTLS(t *testing.T) {
+	key, iv, pt := meshTestData()
+	c := NewCipher(key, &Gost28147_CryptoProParamSetA)
+	ct := make([]byte, len(pt))
+	c.NewCTRMesh(iv).XORKeyStream(ct, pt)
+	testSHA256(t, ct, "ff858f1c589d948a5b8c89e0293bba3b5db9477a8812981c95846d66082c5461")
+}
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestCFBMesh(t *testing.T) {
+	var key [KeySize]byte
+	var iv [BlockSize]byte
+	rand.Read(key[:])
+	rand.Read(iv[:])
+	c := NewCipher(key, SboxDefault)
+	pt := make([]byte, 3*MeshBlockSize+BlockSize)
+	rand.Read(pt)
+	ct := make([]byte, len(pt))
+	c.NewCFBEncrypterMesh(iv).XORKeyStream(ct, pt)
+
+	// First section is ordinary CFB
+	ctPlain := make([]byte, len(pt))
+	c.NewCFBEncrypter(iv).XORKeyStream(ctPlain, pt)
+	if bytes.Compare(ct[:MeshBlockSize], ctPlain[:MeshBlockSize]) != 0 {
+		t.FailNow()
+	}
+	if bytes.Compare(ct[MeshBlockSize:], ctPlain[MeshBlockSize:]) == 0 {
+		t.FailNow()
+	}
+
+	// Second one is CFB with the meshed key and IV
+	var keyMeshed [KeySize]byte
+	c.NewECBDecrypter().CryptBlocks(keyMeshed[:], CryptoProMeshingC[:])
+	cMeshed := NewCipher(keyMeshed, SboxDefault)
+	var ivMeshed [BlockSize]byte
+	cMeshed.Encrypt(ivMeshed[:], ct[MeshBlockSize-BlockSize:MeshBlockSize])
+	cMeshed.NewCFBEncrypter(ivMeshed).XORKeyStream(
+		ctPlain[:MeshBlockSize],
+		pt[MeshBlockSize:2*MeshBlockSize],
+	)
+	if bytes.Compare(ct[MeshBlockSize:2*MeshBlockSize], ctPlain[:MeshBlockSize]) != 0 {
+		t.FailNow()
+	}
+
+	// Decryption in chunks not aligned to blocks
+	fd := c.NewCFBDecrypterMesh(iv)
+	pt2 := make([]byte, len(ct))
+	for i := 0; i < len(ct); i += 13 {
+		end := i + 13
+		if end > len(ct) {
+			end = len(ct)
+		}
+		fd.XORKeyStream(pt2[i:end], ct[i:end])
+	}
+	if bytes.Compare(pt2, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCTRMesh(t *testing.T) {
+	var key [KeySize]byte
+	var iv [BlockSize]byte
+	rand.Read(key[:])
+	rand.Read(iv[:])
+	c := NewCipher(key, SboxDefault)
+	pt := make([]byte, 3*MeshBlockSize+BlockSize)
+	rand.Read(pt)
+	ct := make([]byte, len(pt))
+	c.NewCTRMesh(iv).XORKeyStream(ct, pt)
+
+	ctPlain := make([]byte, len(pt))
+	c.NewCTR(iv).XORKeyStream(ctPlain, pt)
+	if bytes.Compare(ct[:MeshBlockSize], ctPlain[:MeshBlockSize]) != 0 {
+		t.FailNow()
+	}
+	if bytes.Compare(ct[MeshBlockSize:], ctPlain[MeshBlockSize:]) == 0 {
+		t.FailNow()
+	}
+
+	ctr := c.NewCTRMesh(iv)
+	pt2 := make([]byte, len(ct))
+	for i := 0; i < len(ct); i += 5 {
+		end := i + 5
+		if end > len(ct) {
+			end = len(ct)
+		}
+		ctr.XORKeyStream(pt2[i:end], ct[i:end])
+	}
+	if bytes.Compare(pt2, pt) != 0 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,98 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost28147
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+const (
+	UKMSize = 8
+	// Wrapped key consists of UKM, encrypted key and 4 bytes of MAC.
+	WrapSize = UKMSize + KeySize + 4
+)
+
+// GOST 28147-89 key wrap algorithm (RFC 4357 6.1). Content encryption
+// key is encrypted in ECB mode with the cipher's key and authenticated
+// with the MAC, which IV is UKM. Result is UKM | CEK_ENC | CEK_MAC.
+func (c *Cipher) WrapGost(ukm [UKMSize]byte, cek [KeySize]byte) []byte {
+	wrapped := make([]byte, WrapSize)
+	copy(wrapped, ukm[:])
+	c.NewECBEncrypter().CryptBlocks(wrapped[UKMSize:UKMSize+KeySize], cek[:])
+	m, _ := c.NewMAC(4, ukm)
+	m.Write(cek[:])
+	m.Sum(wrapped[:UKMSize+KeySize])
+	return wrapped
+}
+
+// GOST 28147-89 key unwrap algorithm (RFC 4357 6.2).
+func (c *Cipher) UnwrapGost(wrapped []byte) ([]byte, error) {
+	if len(wrapped) != WrapSize {
+		return nil, errors.New("Invalid wrapped key length")
+	}
+	var ukm [UKMSize]byte
+	copy(ukm[:], wrapped[:UKMSize])
+	cek := make([]byte, KeySize)
+	c.NewECBDecrypter().CryptBlocks(cek, wrapped[UKMSize:UKMSize+KeySize])
+	m, _ := c.NewMAC(4, ukm)
+	m.Write(cek)
+	if subtle.ConstantTimeCompare(m.Sum(nil), wrapped[UKMSize+KeySize:]) != 1 {
+		return nil, errors.New("Invalid key MAC")
+	}
+	return cek, nil
+}
+
+// CryptoPro KEK diversification algorithm (RFC 4357 6.5). Returns the
+// cipher with the same S-box and UKM-diversified key.
+func (c *Cipher) DiversifyCryptoPro(ukm [UKMSize]byte) *Cipher {
+	key := *c.key
+	var s1, s2, k nv
+	var s [BlockSize]byte
+	for i := 0; i < 8; i++ {
+		s1, s2 = 0, 0
+		for j := 0; j < 8; j++ {
+			k = nv(key[j*4]) | nv(key[j*4+1])<<8 |
+				nv(key[j*4+2])<<16 | nv(key[j*4+3])<<24
+			if (ukm[i]>>uint(j))&1 > 0 {
+				s1 += k
+			} else {
+				s2 += k
+			}
+		}
+		nvs2block(s2, s1, s[:])
+		NewCipher(key, c.sbox).NewCFBEncrypter(s).XORKeyStream(key[:], key[:])
+	}
+	return NewCipher(key, c.sbox)
+}
+
+// CryptoPro key wrap algorithm (RFC 4357 6.3): GOST 28147-89 key wrap
+// with the UKM-diversified key.
+func (c *Cipher) WrapCryptoPro(ukm [UKMSize]byte, cek [KeySize]byte) []byte {
+	return c.DiversifyCryptoPro(ukm).WrapGost(ukm, cek)
+}
+
+// CryptoPro key unwrap algorithm (RFC 4357 6.4).
+func (c *Cipher) UnwrapCryptoPro(wrapped []byte) ([]byte, error) {
+	if len(wrapped) != WrapSize {
+		return nil, errors.New("Invalid wrapped key length")
+	}
+	var ukm [UKMSize]byte
+	copy(ukm[:], wrapped[:UKMSize])
+	return c.DiversifyCryptoPro(ukm).UnwrapGost(wrapped)
+}
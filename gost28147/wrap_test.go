@@ -0,0 +1,145 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost28147
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestWrapGostSymmetric(t *testing.T) {
+	f := func(kek, cek [KeySize]byte, ukm [UKMSize]byte) bool {
+		c := NewCipher(kek, SboxDefault)
+		wrapped := c.WrapGost(ukm, cek)
+		if bytes.Compare(wrapped[:UKMSize], ukm[:]) != 0 {
+			return false
+		}
+		unwrapped, err := c.UnwrapGost(wrapped)
+		if err != nil {
+			return false
+		}
+		return bytes.Compare(unwrapped, cek[:]) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWrapCryptoProSymmetric(t *testing.T) {
+	f := func(kek, cek [KeySize]byte, ukm [UKMSize]byte) bool {
+		c := NewCipher(kek, SboxDefault)
+		wrapped := c.WrapCryptoPro(ukm, cek)
+		unwrapped, err := c.UnwrapCryptoPro(wrapped)
+		if err != nil {
+			return false
+		}
+		return bytes.Compare(unwrapped, cek[:]) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWrapCorrupted(t *testing.T) {
+	var kek, cek [KeySize]byte
+	var ukm [UKMSize]byte
+	rand.Read(kek[:])
+	rand.Read(cek[:])
+	rand.Read(ukm[:])
+	c := NewCipher(kek, SboxDefault)
+	for _, wrap := range []func([UKMSize]byte, [KeySize]byte) []byte{
+		c.WrapGost, c.WrapCryptoPro,
+	} {
+		for _, i := range []int{0, UKMSize, WrapSize - 1} {
+			wrapped := wrap(ukm, cek)
+			wrapped[i] ^= 0x01
+			if _, err := c.UnwrapGost(wrapped); err == nil {
+				t.FailNow()
+			}
+			if _, err := c.UnwrapCryptoPro(wrapped); err == nil {
+				t.FailNow()
+			}
+		}
+	}
+	if _, err := c.UnwrapGost(make([]byte, WrapSize-1)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestDiversifyCryptoPro(t *testing.T) {
+	var kek [KeySize]byte
+	var ukm1, ukm2 [UKMSize]byte
+	rand.Read(kek[:])
+	rand.Read(ukm1[:])
+	copy(ukm2[:], ukm1[:])
+	ukm2[7] ^= 0x80
+	c := NewCipher(kek, SboxDefault)
+	k1 := c.DiversifyCryptoPro(ukm1).key
+	k2 := c.DiversifyCryptoPro(ukm2).key
+	if bytes.Compare(k1[:], kek[:]) == 0 || bytes.Compare(k1[:], k2[:]) == 0 {
+		t.FailNow()
+	}
+	if bytes.Compare(c.DiversifyCryptoPro(ukm1).key[:], k1[:]) != 0 {
+		t.FailNow()
+	}
+}
+
+// Expected values are computed with GnuTLS 3.7.9 28147-89 key wrap,
+// KEK diversification and MAC implementation with CryptoPro-A S-box.
+func TestWrapGnuTLS(t *testing.T) {
+	var kek, cek [KeySize]byte
+	for i := 0; i < KeySize; i++ {
+		kek[i] = byte(i)
+		cek[i] = byte(0x20 + i)
+	}
+	var ukm [UKMSize]byte
+	copy(ukm[:], hexDecode("0123456789abcdef"))
+	c := NewCipher(kek, &Gost28147_CryptoProParamSetA)
+	if bytes.Compare(c.DiversifyCryptoPro(ukm).key[:], hexDecode(""+
+		"0216e130330b8dbe1228fbbdf00233d9"+
+		"fb0f8f81e2b5eae0c23e519f59a5bb68",
+	)) != 0 {
+		t.FailNow()
+	}
+	wrapped := c.WrapGost(ukm, cek)
+	if bytes.Compare(wrapped, hexDecode(""+
+		"0123456789abcdef"+
+		"2e633beaa39322217e0439bcdfaaf138"+
+		"b7f390172d3418090fcb5c9f019fb766"+
+		"fce4e365",
+	)) != 0 {
+		t.FailNow()
+	}
+	if unwrapped, err := c.UnwrapGost(wrapped); err != nil || bytes.Compare(unwrapped, cek[:]) != 0 {
+		t.FailNow()
+	}
+	wrapped = c.WrapCryptoPro(ukm, cek)
+	if bytes.Compare(wrapped, hexDecode(""+
+		"0123456789abcdef"+
+		"a36c4d47b0880b859950fdbf701a8e35"+
+		"a22dbd08ce97f795843ba777993b25e6"+
+		"d00b416a",
+	)) != 0 {
+		t.FailNow()
+	}
+	if unwrapped, err := c.UnwrapCryptoPro(wrapped); err != nil || bytes.Compare(unwrapped, cek[:]) != 0 {
+		t.FailNow()
+	}
+}
@@ -183,3 +183,10 @@ func TestPrvEqualsTo1(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestPrvTooLong(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	if _, err := NewPrivateKey(c, Mode2001, make([]byte, int(Mode2001)+1)); err == nil {
+		t.FailNow()
+	}
+}
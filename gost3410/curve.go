@@ -29,6 +29,8 @@ var (
 	bigInt3 *big.Int = big.NewInt(3)
 )
 
+var errPointAtInfinity = errors.New("Point at infinity")
+
 type Curve struct {
 	P *big.Int
 	Q *big.Int
@@ -39,10 +41,25 @@ type Curve struct {
 	Bx *big.Int
 	By *big.Int
 
-	// Temporary variable for the add method
-	t  *big.Int
-	tx *big.Int
-	ty *big.Int
+	// Cofactor: number of curve points is Q*Co
+	Co *big.Int
+
+	// Twisted Edwards form parameters, nil for Weierstrass-only curves
+	E *big.Int
+	D *big.Int
+
+	// Field and curve parameters in Montgomery form
+	f  *field
+	a  fe
+	b3 fe
+
+	// Edwards to Weierstrass mapping parameters
+	edS *big.Int
+	edT *big.Int
+	e   fe
+	d   fe
+	s   fe
+	t   fe
 }
 
 func NewCurve(p, q, a, b, bx, by []byte) (*Curve, error) {
@@ -53,9 +70,6 @@ func NewCurve(p, q, a, b, bx, by []byte) (*Curve, error) {
 		B:  bytes2big(b[:]),
 		Bx: bytes2big(bx[:]),
 		By: bytes2big(by[:]),
-		t:  big.NewInt(0),
-		tx: big.NewInt(0),
-		ty: big.NewInt(0),
 	}
 	r1 := big.NewInt(0)
 	r2 := big.NewInt(0)
@@ -72,65 +86,252 @@ func NewCurve(p, q, a, b, bx, by []byte) (*Curve, error) {
 	if r1.Cmp(r2) != 0 {
 		return nil, errors.New("Invalid curve parameters")
 	}
+	if c.P.Bit(0) == 0 || c.P.BitLen() > maxLimbs*64 {
+		return nil, errors.New("Unsupported field size")
+	}
+	// Hasse's bound is much less than Q, so rounded (P+1)/Q is the
+	// cofactor
+	c.Co = big.NewInt(0).Rsh(c.Q, 1)
+	c.Co.Add(c.Co, c.P)
+	c.Co.Add(c.Co, bigInt1)
+	c.Co.Div(c.Co, c.Q)
+	c.f = newField(c.P)
+	c.a = c.f.fromBig(c.A)
+	c.b3 = c.f.fromBig(big.NewInt(0).Mul(c.B, bigInt3))
 	return &c, nil
 }
 
-func (c *Curve) pos(v *big.Int) {
-	if v.Cmp(zero) < 0 {
-		v.Add(v, c.P)
+// Curves are equal if they have the same parameters.
+func (c *Curve) Equal(x *Curve) bool {
+	if c == x {
+		return true
+	}
+	return c.P.Cmp(x.P) == 0 &&
+		c.Q.Cmp(x.Q) == 0 &&
+		c.A.Cmp(x.A) == 0 &&
+		c.B.Cmp(x.B) == 0 &&
+		c.Bx.Cmp(x.Bx) == 0 &&
+		c.By.Cmp(x.By) == 0
+}
+
+// Check that the point is on the curve.
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 || y.Sign() < 0 || y.Cmp(c.P) >= 0 {
+		return false
+	}
+	r1 := big.NewInt(0).Mul(y, y)
+	r1.Mod(r1, c.P)
+	r2 := big.NewInt(0).Mul(x, x)
+	r2.Add(r2, c.A)
+	r2.Mul(r2, x)
+	r2.Add(r2, c.B)
+	r2.Mod(r2, c.P)
+	return r1.Cmp(r2) == 0
+}
+
+// Check that the point is on the curve and belongs to the subgroup of
+// order Q.
+func (c *Curve) validatePoint(x, y *big.Int) error {
+	if !c.IsOnCurve(x, y) {
+		return errors.New("Point is not on the curve")
+	}
+	if c.Co.Cmp(bigInt1) == 0 {
+		return nil
+	}
+	if _, _, err := c.Exp(c.Q, x, y); err != errPointAtInfinity {
+		return errors.New("Point is not in the subgroup")
+	}
+	return nil
+}
+
+// Point in projective coordinates (X:Y:Z), x = X/Z, y = Y/Z.
+type point struct {
+	x, y, z fe
+}
+
+// Point at infinity (0:1:0).
+func (c *Curve) newPoint() *point {
+	p := point{c.f.newElement(), c.f.newElement(), c.f.newElement()}
+	copy(p.y, c.f.one)
+	return &p
+}
+
+func (c *Curve) newPointAffine(x, y *big.Int) *point {
+	return &point{c.f.fromBig(x), c.f.fromBig(y), append(fe{}, c.f.one...)}
+}
+
+func (c *Curve) affine(p *point) (*big.Int, *big.Int, error) {
+	if c.f.isZero(p.z) {
+		return nil, nil, errPointAtInfinity
 	}
+	zi := c.f.newElement()
+	c.f.inv(zi, p.z)
+	x := c.f.newElement()
+	y := c.f.newElement()
+	c.f.mul(x, p.x, zi)
+	c.f.mul(y, p.y, zi)
+	return c.f.toBig(x), c.f.toBig(y), nil
 }
 
-func (c *Curve) add(p1x, p1y, p2x, p2y *big.Int) {
-	if p1x.Cmp(p2x) == 0 && p1y.Cmp(p2y) == 0 {
-		// double
-		c.t.Mul(p1x, p1x)
-		c.t.Mul(c.t, bigInt3)
-		c.t.Add(c.t, c.A)
-		c.tx.Mul(bigInt2, p1y)
-		c.tx.ModInverse(c.tx, c.P)
-		c.t.Mul(c.t, c.tx)
-		c.t.Mod(c.t, c.P)
-	} else {
-		c.tx.Sub(p2x, p1x)
-		c.tx.Mod(c.tx, c.P)
-		c.pos(c.tx)
-		c.ty.Sub(p2y, p1y)
-		c.ty.Mod(c.ty, c.P)
-		c.pos(c.ty)
-		c.t.ModInverse(c.tx, c.P)
-		c.t.Mul(c.t, c.ty)
-		c.t.Mod(c.t, c.P)
-	}
-	c.tx.Mul(c.t, c.t)
-	c.tx.Sub(c.tx, p1x)
-	c.tx.Sub(c.tx, p2x)
-	c.tx.Mod(c.tx, c.P)
-	c.pos(c.tx)
-	c.ty.Sub(p1x, c.tx)
-	c.ty.Mul(c.ty, c.t)
-	c.ty.Sub(c.ty, p1y)
-	c.ty.Mod(c.ty, c.P)
-	c.pos(c.ty)
-	p1x.Set(c.tx)
-	p1y.Set(c.ty)
+func pointCswap(p1, p2 *point, b uint64) {
+	cswap(p1.x, p2.x, b)
+	cswap(p1.y, p2.y, b)
+	cswap(p1.z, p2.z, b)
 }
 
+// Complete addition formulas for prime order short Weierstrass curves
+// (Renes, Costello, Batina, 2015, algorithm 1). They are also valid
+// for doubling, so p3 = p1 + p2 is computed without any branching.
+// p3 may alias p1 or p2.
+func (c *Curve) padd(p3, p1, p2 *point) {
+	f := c.f
+	var buf [9][maxLimbs]uint64
+	t0, t1, t2 := fe(buf[0][:f.n]), fe(buf[1][:f.n]), fe(buf[2][:f.n])
+	t3, t4, t5 := fe(buf[3][:f.n]), fe(buf[4][:f.n]), fe(buf[5][:f.n])
+	x3, y3, z3 := fe(buf[6][:f.n]), fe(buf[7][:f.n]), fe(buf[8][:f.n])
+	f.mul(t0, p1.x, p2.x)
+	f.mul(t1, p1.y, p2.y)
+	f.mul(t2, p1.z, p2.z)
+	f.add(t3, p1.x, p1.y)
+	f.add(t4, p2.x, p2.y)
+	f.mul(t3, t3, t4)
+	f.add(t4, t0, t1)
+	f.sub(t3, t3, t4)
+	f.add(t4, p1.x, p1.z)
+	f.add(t5, p2.x, p2.z)
+	f.mul(t4, t4, t5)
+	f.add(t5, t0, t2)
+	f.sub(t4, t4, t5)
+	f.add(t5, p1.y, p1.z)
+	f.add(x3, p2.y, p2.z)
+	f.mul(t5, t5, x3)
+	f.add(x3, t1, t2)
+	f.sub(t5, t5, x3)
+	f.mul(z3, c.a, t4)
+	f.mul(x3, c.b3, t2)
+	f.add(z3, x3, z3)
+	f.sub(x3, t1, z3)
+	f.add(z3, t1, z3)
+	f.mul(y3, x3, z3)
+	f.add(t1, t0, t0)
+	f.add(t1, t1, t0)
+	f.mul(t2, c.a, t2)
+	f.mul(t4, c.b3, t4)
+	f.add(t1, t1, t2)
+	f.sub(t2, t0, t2)
+	f.mul(t2, c.a, t2)
+	f.add(t4, t4, t2)
+	f.mul(t0, t1, t4)
+	f.add(y3, y3, t0)
+	f.mul(t0, t5, t4)
+	f.mul(x3, t3, x3)
+	f.sub(x3, x3, t0)
+	f.mul(t0, t3, t1)
+	f.mul(z3, z3, t5)
+	f.add(z3, z3, t0)
+	copy(p3.x, x3)
+	copy(p3.y, y3)
+	copy(p3.z, z3)
+}
+
+// Doubling formulas for short Weierstrass curves (Renes, Costello,
+// Batina, 2015, algorithm 3): p3 = 2 * p1. p3 may alias p1.
+func (c *Curve) pdouble(p3, p1 *point) {
+	f := c.f
+	var buf [7][maxLimbs]uint64
+	t0, t1, t2 := fe(buf[0][:f.n]), fe(buf[1][:f.n]), fe(buf[2][:f.n])
+	t3 := fe(buf[3][:f.n])
+	x3, y3, z3 := fe(buf[4][:f.n]), fe(buf[5][:f.n]), fe(buf[6][:f.n])
+	f.mul(t0, p1.x, p1.x)
+	f.mul(t1, p1.y, p1.y)
+	f.mul(t2, p1.z, p1.z)
+	f.mul(t3, p1.x, p1.y)
+	f.add(t3, t3, t3)
+	f.mul(z3, p1.x, p1.z)
+	f.add(z3, z3, z3)
+	f.mul(x3, c.a, z3)
+	f.mul(y3, c.b3, t2)
+	f.add(y3, x3, y3)
+	f.sub(x3, t1, y3)
+	f.add(y3, t1, y3)
+	f.mul(y3, x3, y3)
+	f.mul(x3, t3, x3)
+	f.mul(z3, c.b3, z3)
+	f.mul(t2, c.a, t2)
+	f.sub(t3, t0, t2)
+	f.mul(t3, c.a, t3)
+	f.add(t3, t3, z3)
+	f.add(z3, t0, t0)
+	f.add(t0, z3, t0)
+	f.add(t0, t0, t2)
+	f.mul(t0, t0, t3)
+	f.add(y3, y3, t0)
+	f.mul(t2, p1.y, p1.z)
+	f.add(t2, t2, t2)
+	f.mul(t0, t2, t3)
+	f.sub(x3, x3, t0)
+	f.mul(z3, t2, t1)
+	f.add(z3, z3, z3)
+	f.add(z3, z3, z3)
+	copy(p3.x, x3)
+	copy(p3.y, y3)
+	copy(p3.z, z3)
+}
+
+// Variable time computation of k1*(x1, y1) + k2*(x2, y2) using
+// Shamir's trick with the single inversion at the end. It must be used
+// only with public values, like during signature verification.
+func (c *Curve) expDouble(k1, x1, y1, k2, x2, y2 *big.Int) (*big.Int, *big.Int, error) {
+	p1 := c.newPointAffine(x1, y1)
+	p2 := c.newPointAffine(x2, y2)
+	p12 := c.newPoint()
+	c.padd(p12, p1, p2)
+	r := c.newPoint()
+	bitsLen := k1.BitLen()
+	if k2.BitLen() > bitsLen {
+		bitsLen = k2.BitLen()
+	}
+	for i := bitsLen - 1; i >= 0; i-- {
+		c.pdouble(r, r)
+		switch k1.Bit(i)<<1 | k2.Bit(i) {
+		case 1:
+			c.padd(r, r, p2)
+		case 2:
+			c.padd(r, r, p1)
+		case 3:
+			c.padd(r, r, p12)
+		}
+	}
+	return c.affine(r)
+}
+
+// Scalar multiplication of the point (xS, yS) by degree. It is done
+// with Montgomery ladder over the fixed number of bits (at least the
+// field size) with constant time field arithmetic, so its timing does
+// not depend on the degree value.
 func (c *Curve) Exp(degree, xS, yS *big.Int) (*big.Int, *big.Int, error) {
 	if degree.Cmp(zero) == 0 {
 		return nil, nil, errors.New("Bad degree value")
 	}
-	dg := big.NewInt(0).Sub(degree, bigInt1)
-	tx := big.NewInt(0).Set(xS)
-	ty := big.NewInt(0).Set(yS)
-	cx := big.NewInt(0).Set(xS)
-	cy := big.NewInt(0).Set(yS)
-	for dg.Cmp(zero) != 0 {
-		if dg.Bit(0) == 1 {
-			c.add(tx, ty, cx, cy)
-		}
-		dg.Rsh(dg, 1)
-		c.add(cx, cy, cx, cy)
+	bitsLen := c.f.n * 64
+	if degree.BitLen() > bitsLen {
+		bitsLen = degree.BitLen()
+	}
+	k := big2limbs(degree, (bitsLen+63)/64)
+	if c.IsEdwards() {
+		return c.expEdwards(k, bitsLen, xS, yS)
+	}
+	r0 := c.newPoint()
+	r1 := c.newPointAffine(xS, yS)
+	var bit, swap uint64
+	for i := bitsLen - 1; i >= 0; i-- {
+		bit = (k[i/64] >> uint(i%64)) & 1
+		swap ^= bit
+		pointCswap(r0, r1, swap)
+		swap = bit
+		c.padd(r1, r0, r1)
+		c.pdouble(r0, r0)
 	}
-	return tx, ty, nil
+	pointCswap(r0, r1, swap)
+	return c.affine(r0)
 }
@@ -0,0 +1,145 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func TestDoubleAndAdd(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA)
+	f := func(kRaw [8]byte) bool {
+		k := bytes2big(kRaw[:])
+		k.Add(k, bigInt1)
+		x, y, _ := c.Exp(k, c.Bx, c.By)
+		p1 := c.newPointAffine(x, y)
+		p2 := c.newPointAffine(x, y)
+		c.pdouble(p1, p1)
+		c.padd(p2, p2, p2)
+		x1, y1, _ := c.affine(p1)
+		x2, y2, _ := c.affine(p2)
+		x3, y3, _ := c.Exp(big.NewInt(0).Lsh(k, 1), c.Bx, c.By)
+		return x1.Cmp(x2) == 0 && y1.Cmp(y2) == 0 &&
+			x1.Cmp(x3) == 0 && y1.Cmp(y3) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExpDouble(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	f := func(k1Raw, k2Raw, k3Raw [32]byte) bool {
+		k1 := bytes2big(k1Raw[:])
+		k2 := bytes2big(k2Raw[:])
+		k3 := bytes2big(k3Raw[:])
+		k1.Add(k1, bigInt1)
+		k2.Add(k2, bigInt1)
+		k3.Add(k3, bigInt1)
+		qx, qy, _ := c.Exp(k3, c.Bx, c.By)
+		x, y, err := c.expDouble(k1, c.Bx, c.By, k2, qx, qy)
+		if err != nil {
+			return false
+		}
+		k := big.NewInt(0).Mul(k2, k3)
+		k.Add(k, k1)
+		k.Mod(k, c.Q)
+		xExp, yExp, _ := c.Exp(k, c.Bx, c.By)
+		return x.Cmp(xExp) == 0 && y.Cmp(yExp) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func benchmarkExp(b *testing.B, params CurveParams, mode Mode) {
+	c, _ := NewCurveFromParams(params)
+	raw := make([]byte, int(mode))
+	rand.Read(raw)
+	k := bytes2big(raw)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Exp(k, c.Bx, c.By)
+	}
+}
+
+func BenchmarkExp256(b *testing.B) {
+	benchmarkExp(b, CurveParamsGostR34102001CryptoProA, Mode2001)
+}
+
+func BenchmarkExp512(b *testing.B) {
+	benchmarkExp(b, CurveParamsGostR34102012TC26ParamSetA, Mode2012)
+}
+
+func benchmarkExpDouble(b *testing.B, params CurveParams, mode Mode) {
+	c, _ := NewCurveFromParams(params)
+	raw := make([]byte, int(mode))
+	rand.Read(raw)
+	k1 := bytes2big(raw)
+	rand.Read(raw)
+	k2 := bytes2big(raw)
+	qx, qy, _ := c.Exp(k2, c.Bx, c.By)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.expDouble(k1, c.Bx, c.By, k2, qx, qy)
+	}
+}
+
+func BenchmarkExpDouble256(b *testing.B) {
+	benchmarkExpDouble(b, CurveParamsGostR34102001CryptoProA, Mode2001)
+}
+
+func BenchmarkExpDouble512(b *testing.B) {
+	benchmarkExpDouble(b, CurveParamsGostR34102012TC26ParamSetA, Mode2012)
+}
+
+func TestCofactor(t *testing.T) {
+	for _, tc := range []struct {
+		params CurveParams
+		co     int64
+	}{
+		{CurveParamsGostR34102001cc, 2},
+		{CurveParamsGostR34102001Test, 1},
+		{CurveParamsGostR34102001CryptoProA, 1},
+		{CurveParamsGostR34102001CryptoProB, 1},
+		{CurveParamsGostR34102001CryptoProC, 1},
+		{CurveParamsGostR34102001CryptoProXchA, 1},
+		{CurveParamsGostR34102001CryptoProXchB, 1},
+		{CurveParamsGostR34102012TC26ParamSetA256, 4},
+		{CurveParamsGostR34102012TC26ParamSetB256, 1},
+		{CurveParamsGostR34102012TC26ParamSetC256, 1},
+		{CurveParamsGostR34102012TC26ParamSetD256, 1},
+		{CurveParamsGostR34102012TC26ParamSetA, 1},
+		{CurveParamsGostR34102012TC26ParamSetB, 1},
+		{CurveParamsGostR34102012TC26ParamSetC, 4},
+	} {
+		c, err := NewCurveFromParams(tc.params)
+		if err != nil {
+			t.FailNow()
+		}
+		if c.Co.Cmp(big.NewInt(tc.co)) != 0 {
+			t.FailNow()
+		}
+		if _, _, err = c.Exp(c.Q, c.Bx, c.By); err == nil {
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Curve that also has the twisted Edwards form e*u^2 + v^2 = 1 +
+// d*u^2*v^2, birationally equivalent to its short Weierstrass form.
+// Scalar multiplication is done with complete Edwards addition
+// formulas, but all the external interfaces use Weierstrass
+// coordinates, as the signature and VKO algorithms require.
+func NewCurveEdwards(p, q, a, b, bx, by, e, d []byte) (*Curve, error) {
+	c, err := NewCurve(p, q, a, b, bx, by)
+	if err != nil {
+		return nil, err
+	}
+	c.E = bytes2big(e)
+	c.D = bytes2big(d)
+	// s = (e - d) / 4, t = (e + d) / 6
+	c.edS = big.NewInt(0).Sub(c.E, c.D)
+	c.edS.Mul(c.edS, big.NewInt(0).ModInverse(big.NewInt(4), c.P))
+	c.edS.Mod(c.edS, c.P)
+	c.edT = big.NewInt(0).Add(c.E, c.D)
+	c.edT.Mul(c.edT, big.NewInt(0).ModInverse(big.NewInt(6), c.P))
+	c.edT.Mod(c.edT, c.P)
+	// a = s^2 - 3*t^2, b = 2*t^3 - t*s^2
+	s2 := big.NewInt(0).Mul(c.edS, c.edS)
+	t2 := big.NewInt(0).Mul(c.edT, c.edT)
+	r := big.NewInt(0).Mul(t2, bigInt3)
+	r.Sub(s2, r)
+	if r.Mod(r, c.P).Cmp(c.A) != 0 {
+		return nil, errors.New("Invalid curve parameters")
+	}
+	r.Mul(t2, bigInt2)
+	r.Sub(r, s2)
+	r.Mul(r, c.edT)
+	if r.Mod(r, c.P).Cmp(c.B) != 0 {
+		return nil, errors.New("Invalid curve parameters")
+	}
+	c.e = c.f.fromBig(c.E)
+	c.d = c.f.fromBig(c.D)
+	c.s = c.f.fromBig(c.edS)
+	c.t = c.f.fromBig(c.edT)
+	return c, nil
+}
+
+func (c *Curve) IsEdwards() bool {
+	return c.E != nil
+}
+
+// Convert Weierstrass point coordinates to twisted Edwards ones:
+// u = (x - t) / y, v = (x - t - s) / (x - t + s).
+func (c *Curve) XY2UV(x, y *big.Int) (*big.Int, *big.Int, error) {
+	if !c.IsEdwards() {
+		return nil, nil, errors.New("Non twisted Edwards curve")
+	}
+	xt := big.NewInt(0).Sub(x, c.edT)
+	xt.Mod(xt, c.P)
+	if y.Sign() == 0 {
+		if xt.Sign() != 0 {
+			return nil, nil, errors.New("Point has no twisted Edwards form")
+		}
+		return big.NewInt(0), big.NewInt(0).Sub(c.P, bigInt1), nil
+	}
+	den := big.NewInt(0).Add(xt, c.edS)
+	den.Mod(den, c.P)
+	if den.Sign() == 0 {
+		return nil, nil, errors.New("Point has no twisted Edwards form")
+	}
+	u := big.NewInt(0).ModInverse(y, c.P)
+	u.Mul(u, xt)
+	u.Mod(u, c.P)
+	v := big.NewInt(0).Sub(xt, c.edS)
+	v.Mul(v, den.ModInverse(den, c.P))
+	v.Mod(v, c.P)
+	return u, v, nil
+}
+
+// Convert twisted Edwards point coordinates to Weierstrass ones:
+// x = s * (1 + v) / (1 - v) + t, y = s * (1 + v) / ((1 - v) * u).
+func (c *Curve) UV2XY(u, v *big.Int) (*big.Int, *big.Int, error) {
+	if !c.IsEdwards() {
+		return nil, nil, errors.New("Non twisted Edwards curve")
+	}
+	return c.edwardsAffine(c.newPointEdwards(u, v))
+}
+
+func (c *Curve) newPointEdwards(u, v *big.Int) *point {
+	return &point{c.f.fromBig(u), c.f.fromBig(v), append(fe{}, c.f.one...)}
+}
+
+// Convert projective twisted Edwards point (U:V:Z) to affine
+// Weierstrass coordinates with the single inversion.
+func (c *Curve) edwardsAffine(p *point) (*big.Int, *big.Int, error) {
+	f := c.f
+	zv := f.newElement()
+	f.sub(zv, p.z, p.y)
+	if f.isZero(zv) {
+		return nil, nil, errPointAtInfinity
+	}
+	sv := f.newElement()
+	f.add(sv, p.z, p.y)
+	f.mul(sv, sv, c.s)
+	if f.isZero(p.x) {
+		// (0, -1) point of order 2
+		return f.toBig(c.t), big.NewInt(0), nil
+	}
+	w := f.newElement()
+	f.mul(w, zv, p.x)
+	f.inv(w, w)
+	x := f.newElement()
+	y := f.newElement()
+	f.mul(x, sv, p.x)
+	f.mul(x, x, w)
+	f.add(x, x, c.t)
+	f.mul(y, sv, p.z)
+	f.mul(y, y, w)
+	return f.toBig(x), f.toBig(y), nil
+}
+
+// Unified twisted Edwards addition in projective coordinates
+// (add-2008-bbjlp). They are complete, as e is square and d is not,
+// so they are also used for doubling. p3 may alias p1 or p2.
+func (c *Curve) eadd(p3, p1, p2 *point) {
+	f := c.f
+	var buf [8][maxLimbs]uint64
+	a, b, cc := fe(buf[0][:f.n]), fe(buf[1][:f.n]), fe(buf[2][:f.n])
+	d, e, g := fe(buf[3][:f.n]), fe(buf[4][:f.n]), fe(buf[5][:f.n])
+	h, t := fe(buf[6][:f.n]), fe(buf[7][:f.n])
+	f.mul(a, p1.z, p2.z)
+	f.mul(b, a, a)
+	f.mul(cc, p1.x, p2.x)
+	f.mul(d, p1.y, p2.y)
+	f.mul(e, c.d, cc)
+	f.mul(e, e, d)
+	f.sub(h, b, e)
+	f.add(g, b, e)
+	f.add(t, p1.x, p1.y)
+	f.add(b, p2.x, p2.y)
+	f.mul(t, t, b)
+	f.sub(t, t, cc)
+	f.sub(t, t, d)
+	f.mul(t, t, a)
+	f.mul(p3.x, t, h)
+	f.mul(e, c.e, cc)
+	f.sub(e, d, e)
+	f.mul(e, e, a)
+	f.mul(p3.y, e, g)
+	f.mul(p3.z, h, g)
+}
+
+// Montgomery ladder over twisted Edwards form of the curve.
+func (c *Curve) expEdwards(k []uint64, bitsLen int, xS, yS *big.Int) (*big.Int, *big.Int, error) {
+	u, v, err := c.XY2UV(xS, yS)
+	if err != nil {
+		return nil, nil, err
+	}
+	r0 := c.newPointEdwards(zero, bigInt1)
+	r1 := c.newPointEdwards(u, v)
+	var bit, swap uint64
+	for i := bitsLen - 1; i >= 0; i-- {
+		bit = (k[i/64] >> uint(i%64)) & 1
+		swap ^= bit
+		pointCswap(r0, r1, swap)
+		swap = bit
+		c.eadd(r1, r0, r1)
+		c.eadd(r0, r0, r0)
+	}
+	pointCswap(r0, r1, swap)
+	return c.edwardsAffine(r0)
+}
@@ -0,0 +1,215 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func testEdwardsBasePoint(t *testing.T, params CurveParams, u, v string) {
+	c, err := NewCurveFromParams(params)
+	if err != nil || !c.IsEdwards() {
+		t.FailNow()
+	}
+	uOur, vOur, err := c.XY2UV(c.Bx, c.By)
+	if err != nil {
+		t.FailNow()
+	}
+	uExp, _ := big.NewInt(0).SetString(u, 16)
+	vExp, _ := big.NewInt(0).SetString(v, 16)
+	if uOur.Cmp(uExp) != 0 || vOur.Cmp(vExp) != 0 {
+		t.FailNow()
+	}
+	x, y, err := c.UV2XY(uOur, vOur)
+	if err != nil || x.Cmp(c.Bx) != 0 || y.Cmp(c.By) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestEdwardsBasePoint256(t *testing.T) {
+	testEdwardsBasePoint(
+		t,
+		CurveParamsGostR34102012TC26ParamSetA256,
+		"0D",
+		"60CA1E32AA475B348488C38FAB07649CE7EF8DBE87F22E81F92B2592DBA300E7",
+	)
+}
+
+func TestEdwardsBasePoint512(t *testing.T) {
+	testEdwardsBasePoint(
+		t,
+		CurveParamsGostR34102012TC26ParamSetC,
+		"12",
+		"469AF79D1FB1F5E16B99592B77A01E2A0FDFB0D01794368D9A56117F7B386695"+
+			"22DD4B650CF789EEBF068C5D139732F0905622C04B2BAAE7600303EE73001A3D",
+	)
+}
+
+func TestEdwardsInvalidParams(t *testing.T) {
+	edwards := CurveParamsEdwardsGostR34102012TC26ParamSetA256
+	edwards[1] = []byte{0x01, 0x02, 0x03}
+	_, err := NewCurveFromParamsEdwards(
+		CurveParamsGostR34102012TC26ParamSetA256,
+		edwards,
+	)
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEdwardsExp(t *testing.T) {
+	params := CurveParamsGostR34102012TC26ParamSetC
+	ce, _ := NewCurveFromParams(params)
+	cw, _ := NewCurve(params[0], params[1], params[2], params[3], params[4], params[5])
+	if cw.IsEdwards() {
+		t.FailNow()
+	}
+	f := func(kRaw [64]byte) bool {
+		k := bytes2big(kRaw[:])
+		k.Add(k, bigInt1)
+		xe, ye, err := ce.Exp(k, ce.Bx, ce.By)
+		if err != nil {
+			return false
+		}
+		xw, yw, err := cw.Exp(k, cw.Bx, cw.By)
+		if err != nil {
+			return false
+		}
+		return xe.Cmp(xw) == 0 && ye.Cmp(yw) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := ce.Exp(ce.Q, ce.Bx, ce.By); err == nil {
+		t.FailNow()
+	}
+}
+
+func testEdwardsSignVKO(t *testing.T, params CurveParams, mode Mode) {
+	c, _ := NewCurveFromParams(params)
+	f := func(digest [32]byte, ukmRaw [8]byte) bool {
+		prv1, err := GenPrivateKey(c, mode, rand.Reader)
+		if err != nil {
+			return false
+		}
+		prv2, err := GenPrivateKey(c, mode, rand.Reader)
+		if err != nil {
+			return false
+		}
+		pub1, _ := prv1.PublicKey()
+		pub2, _ := prv2.PublicKey()
+		sign, err := prv1.SignDigest(digest[:], rand.Reader)
+		if err != nil {
+			return false
+		}
+		valid, err := pub1.VerifyDigest(digest[:], sign)
+		if err != nil || !valid {
+			return false
+		}
+		ukm := NewUKM(ukmRaw[:])
+		kek1, _ := prv1.KEK2012256(pub2, ukm)
+		kek2, _ := prv2.KEK2012256(pub1, ukm)
+		return bytes.Compare(kek1, kek2) == 0
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 10}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEdwardsSignVKO256(t *testing.T) {
+	testEdwardsSignVKO(t, CurveParamsGostR34102012TC26ParamSetA256, Mode2001)
+}
+
+func TestEdwardsSignVKO512(t *testing.T) {
+	testEdwardsSignVKO(t, CurveParamsGostR34102012TC26ParamSetC, Mode2012)
+}
+
+// Vectors are computed with libgcrypt 1.10.1: public key is derived
+// from the private one and the signature is made over the digest
+// treated as big-endian integer.
+func testEdwardsGcrypt(t *testing.T, params CurveParams, mode Mode, d, x, y, digest, signature string) {
+	c, err := NewCurveFromParams(params)
+	if err != nil || !c.IsEdwards() {
+		t.FailNow()
+	}
+	raw, _ := hex.DecodeString(d)
+	reverse(raw)
+	prv, err := NewPrivateKey(c, mode, raw)
+	if err != nil {
+		t.FailNow()
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.FailNow()
+	}
+	xExp, _ := big.NewInt(0).SetString(x, 16)
+	yExp, _ := big.NewInt(0).SetString(y, 16)
+	if pub.x.Cmp(xExp) != 0 || pub.y.Cmp(yExp) != 0 {
+		t.FailNow()
+	}
+	dgst, _ := hex.DecodeString(digest)
+	sign, _ := hex.DecodeString(signature)
+	valid, err := pub.VerifyDigest(dgst, sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+	dgst[0] ^= 0x01
+	valid, err = pub.VerifyDigest(dgst, sign)
+	if err != nil || valid {
+		t.FailNow()
+	}
+}
+
+func TestEdwardsGcrypt256(t *testing.T) {
+	testEdwardsGcrypt(
+		t,
+		CurveParamsGostR34102012TC26ParamSetA256,
+		Mode2001,
+		"0102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F20",
+		"5234BAF338163EC5C8F1BF5A5F0DDEBB166D318155C41DC14104D48655D65A6D",
+		"CDEAD736ACDA47AF2CE4598309C14E7FA10426EFEC13085FF8D1FA25EAC8DF9E",
+		"A1A2A3A4A5A6A7A8A9AAABACADAEAFB0B1B2B3B4B5B6B7B8B9BABBBCBDBEBFC0",
+		"0FCF220929B70051712BB03E3CABFB0B1C1B29EC6BE2E1BAF339FD4745D33C28"+
+			"04C771CC0A54281BE748BF2F3F58D34AEE2F89E843E5DD23AD471229BEF99A8F",
+	)
+}
+
+func TestEdwardsGcrypt512(t *testing.T) {
+	testEdwardsGcrypt(
+		t,
+		CurveParamsGostR34102012TC26ParamSetC,
+		Mode2012,
+		"0102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F20"+
+			"2122232425262728292A2B2C2D2E2F303132333435363738393A3B3C3D3E3F40",
+		"FAAF7DE6C70A4A7E2A4143D59118BC95D6C0A78EFA769D591DBE90556876BF86"+
+			"8396064EA36A16217F1C8EA1794766566E02578CD61AA862A0D93AF7E0D0604D",
+		"9AF7C7757454F3447B43F1277E8619FF73304F31A52762E00C315787E6223A82"+
+			"D9BA1842A7B15F2E757F315CF54DA2D93E888A2F0D2F455F5C368147347100E3",
+		"B1B2B3B4B5B6B7B8B9BABBBCBDBEBFC0C1C2C3C4C5C6C7C8C9CACBCCCDCECFD0"+
+			"D1D2D3D4D5D6D7D8D9DADBDCDDDEDFE0E1E2E3E4E5E6E7E8E9EAEBECEDEEEFF0",
+		"3E8B876FA9A8292B2B8F135419B58EDD022C96C36D41FD7F90D5CF906614FF93"+
+			"55062406623EBE93D2604C78E8B0C3F2E89405320C7E09F2F6FD656123265EAD"+
+			"10D56DC87823DBAC5AF7B51CB572E4AEB52F605984FF37F6D21D38B53AD03398"+
+			"4F3209EAF88F0959CCBC67D7614EA68A84C2C5F89EFF3E3B907F7191A4F93628",
+	)
+}
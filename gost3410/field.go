@@ -0,0 +1,199 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// Maximal number of 64-bit limbs in field element (512-bit curves).
+const maxLimbs = 8
+
+// Field element: little-endian 64-bit limbs in Montgomery form.
+type fe []uint64
+
+// Prime field arithmetic over fixed-size elements. All operations,
+// except conversion from/to big.Int and inversion, take constant time
+// independent of the values.
+type field struct {
+	n    int
+	p    []uint64
+	pBig *big.Int
+	pInv uint64 // -p^-1 mod 2^64
+	rr   fe     // R^2 mod p, R = 2^(64n)
+	one  fe     // R mod p
+}
+
+func big2limbs(v *big.Int, n int) []uint64 {
+	buf := make([]byte, n*8)
+	v.FillBytes(buf)
+	z := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		z[i] = binary.BigEndian.Uint64(buf[(n-1-i)*8:])
+	}
+	return z
+}
+
+func limbs2big(x []uint64) *big.Int {
+	buf := make([]byte, len(x)*8)
+	for i := 0; i < len(x); i++ {
+		binary.BigEndian.PutUint64(buf[(len(x)-1-i)*8:], x[i])
+	}
+	return bytes2big(buf)
+}
+
+func newField(p *big.Int) *field {
+	n := (p.BitLen() + 63) / 64
+	f := field{n: n, p: big2limbs(p, n), pBig: p}
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.p[0]*inv
+	}
+	f.pInv = -inv
+	r := big.NewInt(0).Lsh(bigInt1, uint(64*n))
+	f.one = big2limbs(big.NewInt(0).Mod(r, p), n)
+	r.Mul(r, r)
+	f.rr = big2limbs(r.Mod(r, p), n)
+	return &f
+}
+
+func (f *field) newElement() fe {
+	return make(fe, f.n)
+}
+
+// Convert integer to the element in Montgomery form.
+func (f *field) fromBig(v *big.Int) fe {
+	z := fe(big2limbs(big.NewInt(0).Mod(v, f.pBig), f.n))
+	f.mul(z, z, f.rr)
+	return z
+}
+
+// Convert element in Montgomery form back to the integer.
+func (f *field) toBig(x fe) *big.Int {
+	var one [maxLimbs]uint64
+	one[0] = 1
+	z := f.newElement()
+	f.mul(z, x, one[:f.n])
+	return limbs2big(z)
+}
+
+// Reduce t+carry*R, that must be less than 2p, modulo p.
+func (f *field) reduce(z, t []uint64, carry uint64) {
+	var s [maxLimbs]uint64
+	var b uint64
+	for i := 0; i < f.n; i++ {
+		s[i], b = bits.Sub64(t[i], f.p[i], b)
+	}
+	mask := -(carry | (b ^ 1))
+	for i := 0; i < f.n; i++ {
+		z[i] = s[i]&mask | t[i]&^mask
+	}
+}
+
+func (f *field) add(z, x, y fe) {
+	var t [maxLimbs]uint64
+	var c uint64
+	for i := 0; i < f.n; i++ {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	f.reduce(z, t[:f.n], c)
+}
+
+func (f *field) sub(z, x, y fe) {
+	var t [maxLimbs]uint64
+	var b uint64
+	for i := 0; i < f.n; i++ {
+		t[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := 0; i < f.n; i++ {
+		z[i], c = bits.Add64(t[i], f.p[i]&mask, c)
+	}
+}
+
+// Montgomery multiplication (CIOS method): z = x*y/R mod p.
+func (f *field) mul(z, x, y fe) {
+	var t [maxLimbs + 2]uint64
+	var c, cc, hi, lo, m uint64
+	n := f.n
+	for i := 0; i < n; i++ {
+		c = 0
+		for j := 0; j < n; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[n], cc = bits.Add64(t[n], c, 0)
+		t[n+1] = cc
+		m = t[0] * f.pInv
+		hi, lo = bits.Mul64(m, f.p[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m, f.p[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[n-1], cc = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + cc
+	}
+	f.reduce(z, t[:n], t[n])
+}
+
+// Inversion through Fermat's little theorem: z = x^(p-2). Exponent is
+// public, so the timing depends only on the field.
+func (f *field) inv(z, x fe) {
+	e := big.NewInt(0).Sub(f.pBig, bigInt2)
+	r := f.newElement()
+	copy(r, f.one)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		f.mul(r, r, r)
+		if e.Bit(i) == 1 {
+			f.mul(r, r, x)
+		}
+	}
+	copy(z, r)
+}
+
+func (f *field) isZero(x fe) bool {
+	var acc uint64
+	for i := 0; i < f.n; i++ {
+		acc |= x[i]
+	}
+	return acc == 0
+}
+
+// Swap x and y if b equals to 1, do nothing if it is 0.
+func cswap(x, y fe, b uint64) {
+	mask := -b
+	var t uint64
+	for i := 0; i < len(x); i++ {
+		t = (x[i] ^ y[i]) & mask
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
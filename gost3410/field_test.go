@@ -0,0 +1,82 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func testField(t *testing.T, params CurveParams) {
+	c, err := NewCurveFromParams(params)
+	if err != nil {
+		t.FailNow()
+	}
+	f := c.f
+	r := bytes2big(nil)
+	fn := func(xRaw, yRaw [64]byte) bool {
+		x := bytes2big(xRaw[:])
+		x.Mod(x, c.P)
+		y := bytes2big(yRaw[:])
+		y.Mod(y, c.P)
+		xm, ym := f.fromBig(x), f.fromBig(y)
+		if f.toBig(xm).Cmp(x) != 0 {
+			return false
+		}
+		z := f.newElement()
+		f.mul(z, xm, ym)
+		if f.toBig(z).Cmp(r.Mod(r.Mul(x, y), c.P)) != 0 {
+			return false
+		}
+		f.add(z, xm, ym)
+		if f.toBig(z).Cmp(r.Mod(r.Add(x, y), c.P)) != 0 {
+			return false
+		}
+		f.sub(z, xm, ym)
+		if f.toBig(z).Cmp(r.Mod(r.Sub(x, y), c.P)) != 0 {
+			return false
+		}
+		if f.isZero(xm) {
+			return true
+		}
+		f.inv(z, xm)
+		return f.toBig(z).Cmp(r.ModInverse(x, c.P)) == 0
+	}
+	if err := quick.Check(fn, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestField256(t *testing.T) {
+	testField(t, CurveParamsGostR34102001CryptoProA)
+}
+
+func TestField512(t *testing.T) {
+	testField(t, CurveParamsGostR34102012TC26ParamSetA)
+}
+
+func TestExpOrder(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	if _, _, err := c.Exp(c.Q, c.Bx, c.By); err == nil {
+		t.FailNow()
+	}
+	x, y, err := c.Exp(bytes2big(nil).Add(c.Q, bigInt1), c.Bx, c.By)
+	if err != nil || x.Cmp(c.Bx) != 0 || y.Cmp(c.By) != 0 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,112 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/gost34112012512"
+	"github.com/martinlindhe/gogost/gost341194"
+)
+
+// Signature algorithm, defining the hash function used by SignMessage
+// and VerifyMessage. 34.10-2012 256-bit keys have the same size as
+// 34.10-2001 ones, so the key itself can not tell which one to use.
+type Algorithm int
+
+const (
+	// GOST R 34.10-2001 with GOST R 34.11-94 and CryptoPro S-box
+	Algorithm2001 Algorithm = iota + 1
+	// GOST R 34.10-2012 256-bit with Streebog-256
+	Algorithm2012256
+	// GOST R 34.10-2012 512-bit with Streebog-512
+	Algorithm2012512
+)
+
+func (algo Algorithm) newHash() hash.Hash {
+	switch algo {
+	case Algorithm2001:
+		return gost341194.New(&gost28147.GostR3411_94_CryptoProParamSet)
+	case Algorithm2012256:
+		return gost34112012256.New()
+	case Algorithm2012512:
+		return gost34112012512.New()
+	}
+	return nil
+}
+
+// Key's mode the algorithm is used with.
+func (algo Algorithm) Mode() Mode {
+	if algo == Algorithm2012512 {
+		return Mode2012
+	}
+	return Mode2001
+}
+
+func checkAlgorithm(algo Algorithm, mode Mode) error {
+	if algo.newHash() == nil {
+		return errors.New("Unknown signature algorithm")
+	}
+	if algo.Mode() != mode {
+		return errors.New("Signature algorithm does not match key's mode")
+	}
+	return nil
+}
+
+// Digest is treated as little-endian number, as hash functions produce
+// and as most implementations and TC26 test vectors expect.
+func messageDigest(h hash.Hash, msg []byte) []byte {
+	h.Reset()
+	h.Write(msg)
+	digest := h.Sum(nil)
+	reverse(digest)
+	return digest
+}
+
+// Hash the message with the algorithm's hash function and sign it.
+func (prv *PrivateKey) SignMessage(algo Algorithm, msg []byte, rand io.Reader) ([]byte, error) {
+	if err := checkAlgorithm(algo, prv.mode); err != nil {
+		return nil, err
+	}
+	return prv.SignMessageHash(algo.newHash(), msg, rand)
+}
+
+// Hash the message with the given hash function and sign it. Hash is
+// reset before use.
+func (prv *PrivateKey) SignMessageHash(h hash.Hash, msg []byte, rand io.Reader) ([]byte, error) {
+	return prv.SignDigest(messageDigest(h, msg), rand)
+}
+
+// Hash the message with the algorithm's hash function and verify its
+// signature.
+func (pub *PublicKey) VerifyMessage(algo Algorithm, msg, signature []byte) (bool, error) {
+	if err := checkAlgorithm(algo, pub.mode); err != nil {
+		return false, err
+	}
+	return pub.VerifyMessageHash(algo.newHash(), msg, signature)
+}
+
+// Hash the message with the given hash function and verify its
+// signature. Hash is reset before use.
+func (pub *PublicKey) VerifyMessageHash(h hash.Hash, msg, signature []byte) (bool, error) {
+	return pub.VerifyDigest(messageDigest(h, msg), signature)
+}
@@ -0,0 +1,183 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/gost341194"
+)
+
+func TestSignMessage2001(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001CryptoProA)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	f := func(msg []byte) bool {
+		sign, err := prv.SignMessage(Algorithm2001, msg, rand.Reader)
+		if err != nil {
+			return false
+		}
+		if valid, err := pub.VerifyMessage(Algorithm2001, msg, sign); err != nil || !valid {
+			return false
+		}
+		h := gost341194.New(&gost28147.GostR3411_94_CryptoProParamSet)
+		h.Write(msg)
+		digest := h.Sum(nil)
+		reverse(digest)
+		if valid, err := pub.VerifyDigest(digest, sign); err != nil || !valid {
+			return false
+		}
+		valid, err := pub.VerifyMessage(Algorithm2001, append(msg, 0x00), sign)
+		return err == nil && !valid
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 10}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignMessage2012(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA)
+	prv, _ := GenPrivateKey(c, Mode2012, rand.Reader)
+	pub, _ := prv.PublicKey()
+	msg := []byte("message")
+	sign, err := prv.SignMessage(Algorithm2012512, msg, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	if valid, err := pub.VerifyMessage(Algorithm2012512, msg, sign); err != nil || !valid {
+		t.FailNow()
+	}
+	if valid, _ := pub.VerifyMessageHash(gost34112012256.New(), msg, sign); valid {
+		t.FailNow()
+	}
+}
+
+func TestSignMessage2012256(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA256)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	msg := []byte("message")
+	sign, err := prv.SignMessage(Algorithm2012256, msg, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	valid, err := pub.VerifyMessageHash(gost34112012256.New(), msg, sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+	if valid, _ = pub.VerifyMessage(Algorithm2001, msg, sign); valid {
+		t.FailNow()
+	}
+}
+
+func TestSignMessageAlgorithmMismatch(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA256)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	for _, algo := range []Algorithm{0, Algorithm2012512} {
+		if _, err := prv.SignMessage(algo, []byte("message"), rand.Reader); err == nil {
+			t.FailNow()
+		}
+		if _, err := pub.VerifyMessage(algo, []byte("message"), make([]byte, 64)); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignMessageHashReset(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA256)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	h := gost34112012256.New()
+	h.Write([]byte("garbage"))
+	sign, err := prv.SignMessageHash(h, []byte("message"), rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	h.Write([]byte("garbage"))
+	valid, err := pub.VerifyMessage(Algorithm2012256, []byte("message"), sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+	valid, err = pub.VerifyMessageHash(h, []byte("message"), sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+}
+
+// Signatures are made with Nettle 3.8.1 gostdsa_sign over Streebog
+// digest, which it treats as little-endian number, as gost-engine does.
+func TestVerifyMessageNettle(t *testing.T) {
+	msg := []byte("The quick brown fox jumps over the lazy dog")
+	for _, tc := range []struct {
+		params    CurveParams
+		mode      Mode
+		algo      Algorithm
+		prv       string
+		signature string
+	}{
+		{
+			CurveParamsGostR34102001CryptoProA,
+			Mode2001,
+			Algorithm2012256,
+			"7A929ADE789BB9BE10ED359DD39A72C11B60961F49397EEE1D19CE9891EC3B28",
+			"90C222F2D33B86047845ACC5DAAE9A9F50BD2346DC059D5D99D49E1BF7D8011E" +
+				"DE5F8C00504B32826D94E3AC415A318FEF7F0F497DD2195938DC25125AA7F98F",
+		},
+		{
+			CurveParamsGostR34102012TC26ParamSetA,
+			Mode2012,
+			Algorithm2012512,
+			"0BA6048AADAE241BA40936D47756D7C93091A0E8514669700EE7508E508B1020" +
+				"72E8123B2200A0563322DAD2827E2714A2636B7BFD18AADFC62967821FA18DD4",
+			"EF2A1CB6C73DB7A71B54D88EEBE05D619E76BFE864BBC6D24C2A766E99F86BCB" +
+				"4597F5ABA0B28229AC1EE27906FC8BB21377BB4954C41175759C564CD5892124" +
+				"5B76286C139FCCA32955DA8231B411A0EE3AE11BFA86D77B017E8EED0567321D" +
+				"A7C2DA4A4CDE8ACC94161B293BC0E436EFA75242FDB1E8A8555CD92AE93B2D04",
+		},
+	} {
+		c, _ := NewCurveFromParams(tc.params)
+		raw, _ := hex.DecodeString(tc.prv)
+		reverse(raw)
+		prv, err := NewPrivateKey(c, tc.mode, raw)
+		if err != nil {
+			t.FailNow()
+		}
+		pub, _ := prv.PublicKey()
+		signature, _ := hex.DecodeString(tc.signature)
+		valid, err := pub.VerifyMessage(tc.algo, msg, signature)
+		if err != nil || !valid {
+			t.FailNow()
+		}
+		valid, err = pub.VerifyMessage(tc.algo, msg[1:], signature)
+		if err != nil || valid {
+			t.FailNow()
+		}
+		if tc.mode != Mode2001 {
+			continue
+		}
+		if valid, _ = pub.VerifyMessage(Algorithm2001, msg, signature); valid {
+			t.FailNow()
+		}
+	}
+}
@@ -18,6 +18,7 @@
 package gost3410
 
 import (
+	"crypto"
 	"errors"
 	"io"
 	"math/big"
@@ -30,8 +31,8 @@ type PrivateKey struct {
 }
 
 func NewPrivateKey(curve *Curve, mode Mode, raw []byte) (*PrivateKey, error) {
-	if len(raw) != int(mode) {
-		errors.New("Invalid private key length")
+	if len(raw) > int(mode) {
+		return nil, errors.New("Invalid private key length")
 	}
 	key := make([]byte, int(mode))
 	copy(key, raw)
@@ -51,6 +52,14 @@ func GenPrivateKey(curve *Curve, mode Mode, rand io.Reader) (*PrivateKey, error)
 	return NewPrivateKey(curve, mode, raw)
 }
 
+func (prv *PrivateKey) Curve() *Curve {
+	return prv.c
+}
+
+func (prv *PrivateKey) Mode() Mode {
+	return prv.mode
+}
+
 func (prv *PrivateKey) Raw() []byte {
 	raw := pad(prv.key.Bytes(), int(prv.mode))
 	reverse(raw)
@@ -106,3 +115,40 @@ Retry:
 		pad(r.Bytes(), int(prv.mode))...,
 	), nil
 }
+
+// crypto.Signer interface implementation. Nil is returned if public key
+// can not be computed.
+func (prv *PrivateKey) Public() crypto.PublicKey {
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil
+	}
+	return pub
+}
+
+// crypto.Signer interface implementation. Digest must be already
+// computed by the caller. Opts hash function is ignored, as GOST hash
+// functions are not registered in crypto package, but *SignerOpts can
+// be used to choose deterministic nonce generation.
+func (prv *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	o, ok := opts.(*SignerOpts)
+	if !ok || !o.Deterministic {
+		return prv.SignDigest(digest, rand)
+	}
+	var extra []byte
+	if o.Hedged {
+		extra = make([]byte, int(prv.mode))
+		if _, err := io.ReadFull(rand, extra); err != nil {
+			return nil, err
+		}
+	}
+	return prv.SignDigestDeterministic(digest, extra)
+}
+
+func (prv *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	return prv.mode == xx.mode && prv.c.Equal(xx.c) && prv.key.Cmp(xx.key) == 0
+}
@@ -18,6 +18,7 @@
 package gost3410
 
 import (
+	"crypto"
 	"errors"
 	"math/big"
 )
@@ -44,6 +45,32 @@ func NewPublicKey(curve *Curve, mode Mode, raw []byte) (*PublicKey, error) {
 	}, nil
 }
 
+// The same as NewPublicKey, but also validates the key.
+func NewPublicKeyValidated(curve *Curve, mode Mode, raw []byte) (*PublicKey, error) {
+	pub, err := NewPublicKey(curve, mode, raw)
+	if err != nil {
+		return nil, err
+	}
+	if err = pub.Validate(); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// Check that the public key point is on the curve and belongs to the
+// subgroup of prime order Q.
+func (pub *PublicKey) Validate() error {
+	return pub.c.validatePoint(pub.x, pub.y)
+}
+
+func (pub *PublicKey) Curve() *Curve {
+	return pub.c
+}
+
+func (pub *PublicKey) Mode() Mode {
+	return pub.mode
+}
+
 func (pub *PublicKey) Raw() []byte {
 	raw := append(
 		pad(pub.y.Bytes(), int(pub.mode)),
@@ -53,6 +80,17 @@ func (pub *PublicKey) Raw() []byte {
 	return raw
 }
 
+func (pub *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return pub.mode == xx.mode &&
+		pub.c.Equal(xx.c) &&
+		pub.x.Cmp(xx.x) == 0 &&
+		pub.y.Cmp(xx.y) == 0
+}
+
 func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
 	if len(signature) != 2*int(pub.mode) {
 		return false, errors.New("Invalid signature length")
@@ -76,30 +114,9 @@ func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
 	z2.Mul(r, v)
 	z2.Mod(z2, pub.c.Q)
 	z2.Sub(pub.c.Q, z2)
-	p1x, p1y, err := pub.c.Exp(z1, pub.c.Bx, pub.c.By)
+	lm, _, err := pub.c.expDouble(z1, pub.c.Bx, pub.c.By, z2, pub.x, pub.y)
 	if err != nil {
-		return false, err
-	}
-	q1x, q1y, err := pub.c.Exp(z2, pub.x, pub.y)
-	if err != nil {
-		return false, err
-	}
-	lm := big.NewInt(0)
-	lm.Sub(q1x, p1x)
-	if lm.Cmp(zero) < 0 {
-		lm.Add(lm, pub.c.P)
-	}
-	lm.ModInverse(lm, pub.c.P)
-	z1.Sub(q1y, p1y)
-	lm.Mul(lm, z1)
-	lm.Mod(lm, pub.c.P)
-	lm.Mul(lm, lm)
-	lm.Mod(lm, pub.c.P)
-	lm.Sub(lm, p1x)
-	lm.Sub(lm, q1x)
-	lm.Mod(lm, pub.c.P)
-	if lm.Cmp(zero) < 0 {
-		lm.Add(lm, pub.c.P)
+		return false, nil
 	}
 	lm.Mod(lm, pub.c.Q)
 	return lm.Cmp(r) == 0, nil
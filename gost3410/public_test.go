@@ -0,0 +1,132 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func rawPublicKey(mode Mode, x, y *big.Int) []byte {
+	raw := append(pad(y.Bytes(), int(mode)), pad(x.Bytes(), int(mode))...)
+	reverse(raw)
+	return raw
+}
+
+func TestPublicKeyValidate(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001CryptoProA)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	if err := pub.Validate(); err != nil {
+		t.FailNow()
+	}
+	if _, err := NewPublicKeyValidated(c, Mode2001, pub.Raw()); err != nil {
+		t.FailNow()
+	}
+	y := big.NewInt(0).Add(pub.y, bigInt1)
+	if _, err := NewPublicKeyValidated(c, Mode2001, rawPublicKey(Mode2001, pub.x, y)); err == nil {
+		t.FailNow()
+	}
+	y = big.NewInt(0).Add(pub.y, c.P)
+	if c.IsOnCurve(pub.x, y) {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeySmallSubgroup(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetC)
+	// (t, 0) is the point of order 2
+	x, y := c.f.toBig(c.t), big.NewInt(0)
+	if !c.IsOnCurve(x, y) {
+		t.FailNow()
+	}
+	raw := rawPublicKey(Mode2012, x, y)
+	pub, err := NewPublicKey(c, Mode2012, raw)
+	if err != nil {
+		t.FailNow()
+	}
+	if err = pub.Validate(); err == nil {
+		t.FailNow()
+	}
+	if _, err = NewPublicKeyValidated(c, Mode2012, raw); err == nil {
+		t.FailNow()
+	}
+	prv, _ := GenPrivateKey(c, Mode2012, rand.Reader)
+	if _, err = prv.KEK2012512(pub, big.NewInt(1)); err == nil {
+		t.FailNow()
+	}
+	pubValid, _ := prv.PublicKey()
+	if err = pubValid.Validate(); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestKEKInvalidCurve(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	pub.y.Add(pub.y, bigInt1)
+	if _, err := prv.KEK2001(pub, big.NewInt(1)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestSignerInterface(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	prv, _ := GenPrivateKey(c, Mode2001, rand.Reader)
+	var signer crypto.Signer = prv
+	pub, ok := signer.Public().(*PublicKey)
+	if !ok {
+		t.FailNow()
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sign, err := signer.Sign(rand.Reader, digest, crypto.Hash(0))
+	if err != nil {
+		t.FailNow()
+	}
+	valid, err := pub.VerifyDigest(digest, sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+}
+
+func TestEqual(t *testing.T) {
+	c1, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	c2, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	c3, _ := NewCurveFromParams(CurveParamsGostR34102001CryptoProA)
+	raw := make([]byte, 32)
+	rand.Read(raw)
+	prv1, _ := NewPrivateKey(c1, Mode2001, raw)
+	prv2, _ := NewPrivateKey(c2, Mode2001, raw)
+	prv3, _ := NewPrivateKey(c3, Mode2001, raw)
+	if !prv1.Equal(prv2) || prv1.Equal(prv3) || prv1.Equal(raw) {
+		t.FailNow()
+	}
+	pub1, _ := prv1.PublicKey()
+	pub2, _ := prv2.PublicKey()
+	pub3, _ := prv3.PublicKey()
+	if !pub1.Equal(pub2) || pub1.Equal(pub3) || pub1.Equal(prv1) {
+		t.FailNow()
+	}
+	if !prv1.Public().(*PublicKey).Equal(pub2) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,133 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/gost34112012512"
+)
+
+// Signing options for PrivateKey.Sign.
+type SignerOpts struct {
+	// Generate nonce deterministically (RFC 6979) with HMAC_DRBG over
+	// Streebog-256 for 256-bit keys and Streebog-512 for 512-bit ones,
+	// instead of reading it from rand.
+	Deterministic bool
+	// Additionally mix the data read from rand into deterministic nonce
+	// generation (RFC 6979 3.6), making signatures hedged against both
+	// weak random number generators and fault attacks.
+	Hedged bool
+}
+
+func (opts *SignerOpts) HashFunc() crypto.Hash {
+	return crypto.Hash(0)
+}
+
+// RFC 6979 3.2 HMAC_DRBG based deterministic nonce generator. Each Read
+// fills the buffer with the next big-endian nonce candidate k, 0 < k < q.
+type nonceRFC6979 struct {
+	newHash func() hash.Hash
+	q       *big.Int
+	qLen    int
+	k       []byte
+	v       []byte
+}
+
+func (n *nonceRFC6979) bits2int(b []byte) *big.Int {
+	r := bytes2big(b)
+	if len(b)*8 > n.qLen {
+		r.Rsh(r, uint(len(b)*8-n.qLen))
+	}
+	return r
+}
+
+func (n *nonceRFC6979) int2octets(v *big.Int) []byte {
+	return pad(v.Bytes(), (n.qLen+7)/8)
+}
+
+func (n *nonceRFC6979) hmac(data ...[]byte) []byte {
+	m := hmac.New(n.newHash, n.k)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+func newNonceRFC6979(newHash func() hash.Hash, q, x *big.Int, digest, extra []byte) *nonceRFC6979 {
+	n := nonceRFC6979{newHash: newHash, q: q, qLen: q.BitLen()}
+	size := newHash().Size()
+	n.k = make([]byte, size)
+	n.v = make([]byte, size)
+	for i := 0; i < size; i++ {
+		n.v[i] = 0x01
+	}
+	xOctets := n.int2octets(big.NewInt(0).Mod(x, q))
+	h := n.bits2int(digest)
+	hOctets := n.int2octets(h.Mod(h, q))
+	n.k = n.hmac(n.v, []byte{0x00}, xOctets, hOctets, extra)
+	n.v = n.hmac(n.v)
+	n.k = n.hmac(n.v, []byte{0x01}, xOctets, hOctets, extra)
+	n.v = n.hmac(n.v)
+	return &n
+}
+
+func (n *nonceRFC6979) next() *big.Int {
+	rLen := (n.qLen + 7) / 8
+	for {
+		t := make([]byte, 0, rLen+len(n.v))
+		for len(t) < rLen {
+			n.v = n.hmac(n.v)
+			t = append(t, n.v...)
+		}
+		k := n.bits2int(t[:rLen])
+		n.k = n.hmac(n.v, []byte{0x00})
+		n.v = n.hmac(n.v)
+		if k.Sign() > 0 && k.Cmp(n.q) < 0 {
+			return k
+		}
+	}
+}
+
+func (n *nonceRFC6979) Read(p []byte) (int, error) {
+	k := n.next().Bytes()
+	if len(k) > len(p) {
+		return 0, io.ErrShortBuffer
+	}
+	copy(p, pad(k, len(p)))
+	return len(p), nil
+}
+
+// Sign digest with the nonce generated deterministically from the
+// private key and digest (RFC 6979), using HMAC_DRBG over Streebog.
+// Optional extra data is mixed into nonce generation.
+func (prv *PrivateKey) SignDigestDeterministic(digest, extra []byte) ([]byte, error) {
+	newHash := func() hash.Hash { return gost34112012512.New() }
+	if prv.mode == Mode2001 {
+		newHash = func() hash.Hash { return gost34112012256.New() }
+	}
+	return prv.SignDigest(
+		digest,
+		newNonceRFC6979(newHash, prv.c.Q, prv.key, digest, extra),
+	)
+}
@@ -0,0 +1,127 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+// Test vectors taken from RFC 6979 A.2.5 (P-256, SHA-256)
+func TestNonceRFC6979(t *testing.T) {
+	q, _ := big.NewInt(0).SetString("FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551", 16)
+	x, _ := big.NewInt(0).SetString("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", 16)
+	for _, tc := range []struct {
+		msg string
+		k   string
+	}{
+		{"sample", "A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60"},
+		{"test", "D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0"},
+	} {
+		digest := sha256.Sum256([]byte(tc.msg))
+		n := newNonceRFC6979(sha256.New, q, x, digest[:], nil)
+		kExpected, _ := big.NewInt(0).SetString(tc.k, 16)
+		k := make([]byte, 32)
+		n.Read(k)
+		if bytes2big(k).Cmp(kExpected) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignDigestDeterministic(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA)
+	prv, _ := GenPrivateKey(c, Mode2012, rand.Reader)
+	pub, _ := prv.PublicKey()
+	f := func(digest [64]byte) bool {
+		sign1, err := prv.SignDigestDeterministic(digest[:], nil)
+		if err != nil {
+			return false
+		}
+		sign2, _ := prv.Sign(rand.Reader, digest[:], &SignerOpts{Deterministic: true})
+		if bytes.Compare(sign1, sign2) != 0 {
+			return false
+		}
+		sign3, _ := prv.Sign(rand.Reader, digest[:], &SignerOpts{Deterministic: true, Hedged: true})
+		if bytes.Compare(sign1, sign3) == 0 {
+			return false
+		}
+		for _, sign := range [][]byte{sign1, sign3} {
+			if valid, err := pub.VerifyDigest(digest[:], sign); err != nil || !valid {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 10}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Vectors are computed with libgcrypt 1.10.1: its ECDSA RFC 6979 nonce
+// with Streebog is recovered from the signature and used to make the
+// 34.10 one, verified by libgcrypt too.
+func TestSignDigestDeterministicVectors(t *testing.T) {
+	for _, tc := range []struct {
+		params    CurveParams
+		mode      Mode
+		prv       string
+		digest    string
+		signature string
+	}{
+		{
+			CurveParamsGostR34102001CryptoProA,
+			Mode2001,
+			"7A929ADE789BB9BE10ED359DD39A72C11B60961F49397EEE1D19CE9891EC3B28",
+			"2DFBC1B372D89A1188C09C52E0EEC61FCE52032AB1022E8E67ECE6672B043EE5",
+			"D11B2A39C2D04619B6F04747C0DC0DDC2518BF0370550FB652832F370128FBED" +
+				"DE90D1F27AC567B26F225E5867C3C7A5621A286E7E48A43C2B46BC1CC5324967",
+		},
+		{
+			CurveParamsGostR34102012TC26ParamSetA,
+			Mode2012,
+			"0BA6048AADAE241BA40936D47756D7C93091A0E8514669700EE7508E508B1020" +
+				"72E8123B2200A0563322DAD2827E2714A2636B7BFD18AADFC62967821FA18DD4",
+			"3754F3CFACC9E0615C4F4A7C4D8DAB531B09B6F9C170C533A71D147035B0C591" +
+				"7184EE536593F4414339976C647C5D5A407ADEDB1D560C4FC6777D2972075B8C",
+			"117404D5AB88A85042B6A97A510B7410BEFBCAE9F173B8D0AAF47EDC530F74B7" +
+				"76837C44438AF69382C2CFE044269927C63C22DA532E9B79A64343823582333F" +
+				"FC73B64E40A2890251C274A201099638E0595078F0AC89D76125982A2A650821" +
+				"F96CA53F85E84C3FCEBCD6449F77FBC726883AF044F716E73D63A4E459166A3D",
+		},
+	} {
+		c, _ := NewCurveFromParams(tc.params)
+		raw, _ := hex.DecodeString(tc.prv)
+		reverse(raw)
+		prv, err := NewPrivateKey(c, tc.mode, raw)
+		if err != nil {
+			t.FailNow()
+		}
+		digest, _ := hex.DecodeString(tc.digest)
+		signature, _ := hex.DecodeString(tc.signature)
+		sign, err := prv.SignDigestDeterministic(digest, nil)
+		if err != nil || bytes.Compare(sign, signature) != 0 {
+			t.FailNow()
+		}
+	}
+}
@@ -21,12 +21,22 @@ import (
 	"math/big"
 )
 
+// Compute (Co * UKM * prv) * pub point, where Co is the curve's
+// cofactor. Public key is validated, so invalid curve and small
+// subgroup points are refused.
 func (prv *PrivateKey) KEK(pub *PublicKey, ukm *big.Int) ([]byte, error) {
+	if err := prv.c.validatePoint(pub.x, pub.y); err != nil {
+		return nil, err
+	}
 	keyX, keyY, err := prv.c.Exp(prv.key, pub.x, pub.y)
 	if err != nil {
 		return nil, err
 	}
-	keyX, keyY, err = prv.c.Exp(ukm, keyX, keyY)
+	keyX, keyY, err = prv.c.Exp(
+		big.NewInt(0).Mul(ukm, prv.c.Co),
+		keyX,
+		keyY,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -22,6 +22,8 @@ import (
 	"encoding/hex"
 	"testing"
 	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost28147"
 )
 
 func TestVKO2001(t *testing.T) {
@@ -67,3 +69,34 @@ func TestRandomVKO2001(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestVKO2001KeyWrap(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102001Test)
+	f := func(prvRaw1, prvRaw2, cek [32]byte, ukmRaw [8]byte) bool {
+		prv1, err := NewPrivateKey(c, Mode2001, prvRaw1[:])
+		if err != nil {
+			return false
+		}
+		prv2, err := NewPrivateKey(c, Mode2001, prvRaw2[:])
+		if err != nil {
+			return false
+		}
+		pub1, _ := prv1.PublicKey()
+		pub2, _ := prv2.PublicKey()
+		ukm := NewUKM(ukmRaw[:])
+		kekRaw1, _ := prv1.KEK2001(pub2, ukm)
+		kekRaw2, _ := prv2.KEK2001(pub1, ukm)
+		var kek1, kek2 [gost28147.KeySize]byte
+		copy(kek1[:], kekRaw1)
+		copy(kek2[:], kekRaw2)
+		wrapped := gost28147.NewCipher(kek1, gost28147.SboxDefault).WrapCryptoPro(ukmRaw, cek)
+		unwrapped, err := gost28147.NewCipher(kek2, gost28147.SboxDefault).UnwrapCryptoPro(wrapped)
+		if err != nil {
+			return false
+		}
+		return bytes.Compare(unwrapped, cek[:]) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
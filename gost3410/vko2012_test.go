@@ -22,6 +22,8 @@ import (
 	"encoding/hex"
 	"testing"
 	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost34112012256"
 )
 
 func TestVKO2012256(t *testing.T) {
@@ -70,6 +72,36 @@ func TestRandomVKO2012256(t *testing.T) {
 	}
 }
 
+// 34.10-2012 256-bit keys are used in Mode2001
+func TestVKO2012256With256BitKeys(t *testing.T) {
+	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA256)
+	f := func(prvRaw1 [32]byte, prvRaw2 [32]byte, ukmRaw [8]byte) bool {
+		prv1, err := NewPrivateKey(c, Mode2001, prvRaw1[:])
+		if err != nil {
+			return false
+		}
+		prv2, err := NewPrivateKey(c, Mode2001, prvRaw2[:])
+		if err != nil {
+			return false
+		}
+		pub1, _ := prv1.PublicKey()
+		pub2, _ := prv2.PublicKey()
+		ukm := NewUKM(ukmRaw[:])
+		kek1, err := prv1.KEK2012256(pub2, ukm)
+		if err != nil {
+			return false
+		}
+		kek2, _ := prv2.KEK2012256(pub1, ukm)
+		key, _ := prv1.KEK(pub2, ukm)
+		h := gost34112012256.New()
+		h.Write(key)
+		return bytes.Compare(kek1, kek2) == 0 && bytes.Compare(kek1, h.Sum(nil)) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestVKO2012512(t *testing.T) {
 	c, _ := NewCurveFromParams(CurveParamsGostR34102012TC26ParamSetA)
 	ukmRaw, _ := hex.DecodeString("1d80603c8544c727")
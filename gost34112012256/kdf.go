@@ -0,0 +1,56 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+func newHash() hash.Hash {
+	return New()
+}
+
+// KDF_GOSTR3411_2012_256 key derivation function (RFC 7836 4.5).
+func KDF(key, label, seed []byte) []byte {
+	return KDFTree(key, label, seed, 1, Size)
+}
+
+// KDF_TREE_GOSTR3411_2012_256 key derivation function (R 50.1.113-2016,
+// RFC 7836 4.5): HMAC-Streebog-256 in counter mode. Counter is r bytes
+// long, length is the output size in bytes.
+func KDFTree(key, label, seed []byte, r, length int) []byte {
+	bitLen := big.NewInt(int64(8 * length)).Bytes()
+	ctr := make([]byte, r)
+	out := make([]byte, 0, length+Size)
+	m := hmac.New(newHash, key)
+	for i := 1; len(out) < length; i++ {
+		for j, v := 0, i; j < r; j, v = j+1, v>>8 {
+			ctr[r-1-j] = byte(v)
+		}
+		m.Reset()
+		m.Write(ctr)
+		m.Write(label)
+		m.Write([]byte{0x00})
+		m.Write(seed)
+		m.Write(bitLen)
+		out = m.Sum(out)
+	}
+	return out[:length]
+}
@@ -0,0 +1,74 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// GOST R 34.12-2015 64-bit (Магма (Magma)) block cipher.
+// RFC 8891.
+package gost341264
+
+import (
+	"github.com/martinlindhe/gogost/gost28147"
+)
+
+const (
+	BlockSize = 8
+	KeySize   = 32
+)
+
+// Magma is the 28147-89 cipher with fixed id-tc26-gost-28147-param-Z
+// S-box and big-endian key and block serialization. It is implemented
+// on top of the gost28147 one with byte order conversion.
+type Cipher struct {
+	c *gost28147.Cipher
+}
+
+func NewCipher(key [KeySize]byte) *Cipher {
+	var keyCompatible [KeySize]byte
+	for i := 0; i < KeySize/4; i++ {
+		for j := 0; j < 4; j++ {
+			keyCompatible[i*4+j] = key[i*4+3-j]
+		}
+	}
+	return &Cipher{gost28147.NewCipher(keyCompatible, &gost28147.Gost28147_tc26_ParamZ)}
+}
+
+func (c *Cipher) BlockSize() int {
+	return BlockSize
+}
+
+func blockReverse(dst, src []byte) {
+	for i, j := 0, BlockSize-1; i < j; i, j = i+1, j-1 {
+		dst[i], dst[j] = src[j], src[i]
+	}
+}
+
+// Encrypt single block.
+// If provided slices are shorter than the block size, then it will panic.
+func (c *Cipher) Encrypt(dst, src []byte) {
+	var blk [BlockSize]byte
+	blockReverse(blk[:], src)
+	c.c.Encrypt(blk[:], blk[:])
+	blockReverse(dst, blk[:])
+}
+
+// Decrypt single block.
+// If provided slices are shorter than the block size, then it will panic.
+func (c *Cipher) Decrypt(dst, src []byte) {
+	var blk [BlockSize]byte
+	blockReverse(blk[:], src)
+	c.c.Decrypt(blk[:], blk[:])
+	blockReverse(dst, blk[:])
+}
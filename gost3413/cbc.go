@@ -0,0 +1,94 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+type CBCEncrypter struct {
+	b cipher.Block
+	r []byte
+}
+
+// Cipher block chaining mode of operation. IV is the initial value of
+// the shift register, that must be z blocks long (z >= 1). Data length
+// must be a multiple of the block size.
+func NewCBCEncrypter(b cipher.Block, iv []byte) (*CBCEncrypter, error) {
+	bs := b.BlockSize()
+	if len(iv) == 0 || len(iv)%bs != 0 {
+		return nil, errors.New("Invalid IV size")
+	}
+	r := make([]byte, len(iv))
+	copy(r, iv)
+	return &CBCEncrypter{b, r}, nil
+}
+
+func (e *CBCEncrypter) BlockSize() int {
+	return e.b.BlockSize()
+}
+
+func (e *CBCEncrypter) CryptBlocks(dst, src []byte) {
+	bs := e.b.BlockSize()
+	checkBlocks(bs, dst, src)
+	var n int
+	for i := 0; i < len(src); i += bs {
+		for n = 0; n < bs; n++ {
+			dst[i+n] = src[i+n] ^ e.r[n]
+		}
+		e.b.Encrypt(dst[i:i+bs], dst[i:i+bs])
+		copy(e.r, e.r[bs:])
+		copy(e.r[len(e.r)-bs:], dst[i:i+bs])
+	}
+}
+
+type CBCDecrypter struct {
+	b   cipher.Block
+	r   []byte
+	blk []byte
+}
+
+func NewCBCDecrypter(b cipher.Block, iv []byte) (*CBCDecrypter, error) {
+	bs := b.BlockSize()
+	if len(iv) == 0 || len(iv)%bs != 0 {
+		return nil, errors.New("Invalid IV size")
+	}
+	r := make([]byte, len(iv))
+	copy(r, iv)
+	return &CBCDecrypter{b, r, make([]byte, bs)}, nil
+}
+
+func (d *CBCDecrypter) BlockSize() int {
+	return d.b.BlockSize()
+}
+
+func (d *CBCDecrypter) CryptBlocks(dst, src []byte) {
+	bs := d.b.BlockSize()
+	checkBlocks(bs, dst, src)
+	var n int
+	for i := 0; i < len(src); i += bs {
+		copy(d.blk, src[i:i+bs])
+		d.b.Decrypt(dst[i:i+bs], src[i:i+bs])
+		for n = 0; n < bs; n++ {
+			dst[i+n] ^= d.r[n]
+		}
+		copy(d.r, d.r[bs:])
+		copy(d.r[len(d.r)-bs:], d.blk)
+	}
+}
@@ -0,0 +1,112 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestCBCInterface(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	e, _ := NewCBCEncrypter(c, kuzIV)
+	d, _ := NewCBCDecrypter(c, kuzIV)
+	var _ cipher.BlockMode = e
+	var _ cipher.BlockMode = d
+}
+
+func TestCBCKuznechik(t *testing.T) {
+	ct := hexDecode("" +
+		"689972d4a085fa4d90e52e3d6d7dcc27" +
+		"2826e661b478eca6af1e8e448d5ea5ac" +
+		"fe7babf1e91999e85640e8b0f49d90d0" +
+		"167688065a895c631a2d9a1560b63970",
+	)
+	c := gost3412.NewCipher(kuzKey)
+	e, err := NewCBCEncrypter(c, kuzIV)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(kuzPT))
+	e.CryptBlocks(tmp, kuzPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	d, _ := NewCBCDecrypter(c, kuzIV)
+	d.CryptBlocks(tmp, tmp)
+	if bytes.Compare(tmp, kuzPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCBCMagma(t *testing.T) {
+	ct := hexDecode("" +
+		"96d1b05eea683919" +
+		"aff76129abb937b9" +
+		"5058b4a1c4bc0019" +
+		"20b78b1a7cd7e667",
+	)
+	c := gost341264.NewCipher(magmaKey)
+	e, err := NewCBCEncrypter(c, magmaIV)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(magmaPT))
+	e.CryptBlocks(tmp, magmaPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	d, _ := NewCBCDecrypter(c, magmaIV)
+	d.CryptBlocks(tmp, tmp)
+	if bytes.Compare(tmp, magmaPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCBCRandom(t *testing.T) {
+	var key [gost341264.KeySize]byte
+	rand.Read(key[:])
+	c := gost341264.NewCipher(key)
+	f := func(iv [3 * gost341264.BlockSize]byte, pt []byte) bool {
+		pt = Pad2(pt, gost341264.BlockSize)
+		ct := make([]byte, len(pt))
+		e, _ := NewCBCEncrypter(c, iv[:])
+		e.CryptBlocks(ct, pt)
+		d, _ := NewCBCDecrypter(c, iv[:])
+		d.CryptBlocks(ct, ct)
+		return bytes.Compare(ct, pt) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCBCPartialBlocks(t *testing.T) {
+	c := gost341264.NewCipher(magmaKey)
+	iv := make([]byte, 8)
+	e, _ := NewCBCEncrypter(c, iv)
+	testPartialBlocks(t, e)
+	d, _ := NewCBCDecrypter(c, iv)
+	testPartialBlocks(t, d)
+}
@@ -0,0 +1,106 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+type cfb struct {
+	b       cipher.Block
+	r       []byte
+	blk     []byte
+	gamma   []byte
+	segment []byte
+	used    int
+	decrypt bool
+}
+
+func newCFB(b cipher.Block, iv []byte, s int, decrypt bool) (*cfb, error) {
+	bs := b.BlockSize()
+	if s <= 0 || s > bs {
+		return nil, errors.New("Invalid s parameter")
+	}
+	if len(iv) == 0 || len(iv)%bs != 0 {
+		return nil, errors.New("Invalid IV size")
+	}
+	r := make([]byte, len(iv))
+	copy(r, iv)
+	return &cfb{
+		b:       b,
+		r:       r,
+		blk:     make([]byte, bs),
+		gamma:   make([]byte, s),
+		segment: make([]byte, s),
+		used:    s,
+		decrypt: decrypt,
+	}, nil
+}
+
+func (c *cfb) XORKeyStream(dst, src []byte) {
+	s := len(c.gamma)
+	var ct byte
+	for i := 0; i < len(src); i++ {
+		if c.used == s {
+			c.b.Encrypt(c.blk, c.r[:len(c.blk)])
+			copy(c.gamma, c.blk)
+			c.used = 0
+		}
+		if c.decrypt {
+			ct = src[i]
+			dst[i] = ct ^ c.gamma[c.used]
+		} else {
+			dst[i] = src[i] ^ c.gamma[c.used]
+			ct = dst[i]
+		}
+		c.segment[c.used] = ct
+		c.used++
+		if c.used == s {
+			copy(c.r, c.r[s:])
+			copy(c.r[len(c.r)-s:], c.segment)
+		}
+	}
+}
+
+type CFBEncrypter struct {
+	*cfb
+}
+
+// Cipher feedback mode of operation. IV is the initial value of the
+// shift register, that must be z blocks long (z >= 1). s is the size
+// of the feedback segment in bytes: 0 < s <= block size.
+func NewCFBEncrypter(b cipher.Block, iv []byte, s int) (*CFBEncrypter, error) {
+	c, err := newCFB(b, iv, s, false)
+	if err != nil {
+		return nil, err
+	}
+	return &CFBEncrypter{c}, nil
+}
+
+type CFBDecrypter struct {
+	*cfb
+}
+
+func NewCFBDecrypter(b cipher.Block, iv []byte, s int) (*CFBDecrypter, error) {
+	c, err := newCFB(b, iv, s, true)
+	if err != nil {
+		return nil, err
+	}
+	return &CFBDecrypter{c}, nil
+}
@@ -0,0 +1,111 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestCFBInterface(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	e, _ := NewCFBEncrypter(c, kuzIV, gost3412.BlockSize)
+	d, _ := NewCFBDecrypter(c, kuzIV, gost3412.BlockSize)
+	var _ cipher.Stream = e
+	var _ cipher.Stream = d
+}
+
+func TestCFBKuznechik(t *testing.T) {
+	ct := hexDecode("" +
+		"81800a59b1842b24ff1f795e897abd95" +
+		"ed5b47a7048cfab48fb521369d9326bf" +
+		"79f2a8eb5cc68d38842d264e97a238b5" +
+		"4ffebecd4e922de6c75bd9dd44fbf4d1",
+	)
+	c := gost3412.NewCipher(kuzKey)
+	e, err := NewCFBEncrypter(c, kuzIV, gost3412.BlockSize)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(kuzPT))
+	e.XORKeyStream(tmp, kuzPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	d, _ := NewCFBDecrypter(c, kuzIV, gost3412.BlockSize)
+	d.XORKeyStream(tmp, tmp)
+	if bytes.Compare(tmp, kuzPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCFBMagma(t *testing.T) {
+	ct := hexDecode("" +
+		"db37e0e266903c83" +
+		"0d46644c1f9a089c" +
+		"24bdd2035315d38b" +
+		"bcc0321421075505",
+	)
+	c := gost341264.NewCipher(magmaKey)
+	iv := magmaIV[:2*gost341264.BlockSize]
+	e, err := NewCFBEncrypter(c, iv, gost341264.BlockSize)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(magmaPT))
+	e.XORKeyStream(tmp, magmaPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	d, _ := NewCFBDecrypter(c, iv, gost341264.BlockSize)
+	d.XORKeyStream(tmp, tmp)
+	if bytes.Compare(tmp, magmaPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCFBRandom(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	c := gost3412.NewCipher(key)
+	f := func(iv [2 * gost3412.BlockSize]byte, pt []byte, s uint8) bool {
+		segment := 1 + int(s)%gost3412.BlockSize
+		ct := make([]byte, len(pt))
+		e, _ := NewCFBEncrypter(c, iv[:], segment)
+		e.XORKeyStream(ct, pt)
+		d, _ := NewCFBDecrypter(c, iv[:], segment)
+		pt2 := make([]byte, len(ct))
+		for i := 0; i < len(ct); i += 3 {
+			end := i + 3
+			if end > len(ct) {
+				end = len(ct)
+			}
+			d.XORKeyStream(pt2[i:end], ct[i:end])
+		}
+		return bytes.Compare(pt2, pt) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,65 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+type CTR struct {
+	b     cipher.Block
+	ctr   []byte
+	gamma []byte
+	used  int
+}
+
+// Counter mode of operation. IV must be half of the block size long:
+// initial counter value is IV concatenated with zero half-block.
+func NewCTR(b cipher.Block, iv []byte) (*CTR, error) {
+	bs := b.BlockSize()
+	if len(iv) != bs/2 {
+		return nil, errors.New("Invalid IV size")
+	}
+	ctr := make([]byte, bs)
+	copy(ctr, iv)
+	gamma := make([]byte, bs)
+	return &CTR{b: b, ctr: ctr, gamma: gamma, used: bs}, nil
+}
+
+// Increment big-endian counter block by one modulo 2^n.
+func ctrIncr(ctr []byte) {
+	for i := len(ctr) - 1; i >= 0; i-- {
+		ctr[i]++
+		if ctr[i] != 0 {
+			break
+		}
+	}
+}
+
+func (c *CTR) XORKeyStream(dst, src []byte) {
+	for i := 0; i < len(src); i++ {
+		if c.used == len(c.gamma) {
+			c.b.Encrypt(c.gamma, c.ctr)
+			ctrIncr(c.ctr)
+			c.used = 0
+		}
+		dst[i] = src[i] ^ c.gamma[c.used]
+		c.used++
+	}
+}
@@ -0,0 +1,110 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestCTRInterface(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	ctr, _ := NewCTR(c, make([]byte, gost3412.BlockSize/2))
+	var _ cipher.Stream = ctr
+}
+
+func TestCTRKuznechik(t *testing.T) {
+	ct := hexDecode("" +
+		"f195d8bec10ed1dbd57b5fa240bda1b8" +
+		"85eee733f6a13e5df33ce4b33c45dee4" +
+		"a5eae88be6356ed3d5e877f13564a3a5" +
+		"cb91fab1f20cbab6d1c6d15820bdba73",
+	)
+	c := gost3412.NewCipher(kuzKey)
+	iv := hexDecode("1234567890abcef0")
+	ctr, err := NewCTR(c, iv)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(kuzPT))
+	ctr.XORKeyStream(tmp, kuzPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	ctr, _ = NewCTR(c, iv)
+	ctr.XORKeyStream(tmp, tmp)
+	if bytes.Compare(tmp, kuzPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCTRMagma(t *testing.T) {
+	ct := hexDecode("" +
+		"4e98110c97b7b93c" +
+		"3e250d93d6e85d69" +
+		"136d868807b2dbef" +
+		"568eb680ab52a12d",
+	)
+	c := gost341264.NewCipher(magmaKey)
+	iv := hexDecode("12345678")
+	ctr, err := NewCTR(c, iv)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(magmaPT))
+	ctr.XORKeyStream(tmp, magmaPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCTRInvalidIV(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	if _, err := NewCTR(c, make([]byte, gost3412.BlockSize)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCTRRandom(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	c := gost3412.NewCipher(key)
+	f := func(ivRaw [gost3412.BlockSize / 2]byte, pt []byte, split uint8) bool {
+		ct := make([]byte, len(pt))
+		ctr, _ := NewCTR(c, ivRaw[:])
+		ctr.XORKeyStream(ct, pt)
+		s := int(split)
+		if s > len(pt) {
+			s = len(pt)
+		}
+		ct2 := make([]byte, len(pt))
+		ctr, _ = NewCTR(c, ivRaw[:])
+		ctr.XORKeyStream(ct2[:s], pt[:s])
+		ctr.XORKeyStream(ct2[s:], pt[s:])
+		return bytes.Compare(ct, ct2) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,75 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+)
+
+type ECBEncrypter struct {
+	b cipher.Block
+}
+
+// Electronic codebook mode of operation. Data length must be a
+// multiple of the block size, so use one of the padding methods first.
+func NewECBEncrypter(b cipher.Block) *ECBEncrypter {
+	return &ECBEncrypter{b}
+}
+
+// Panic like crypto/cipher modes do if src is not full blocks or dst
+// is shorter than src.
+func checkBlocks(bs int, dst, src []byte) {
+	if len(src)%bs != 0 {
+		panic("gost3413: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("gost3413: output smaller than input")
+	}
+}
+
+func (e *ECBEncrypter) CryptBlocks(dst, src []byte) {
+	bs := e.b.BlockSize()
+	checkBlocks(bs, dst, src)
+	for i := 0; i < len(src); i += bs {
+		e.b.Encrypt(dst[i:i+bs], src[i:i+bs])
+	}
+}
+
+func (e *ECBEncrypter) BlockSize() int {
+	return e.b.BlockSize()
+}
+
+type ECBDecrypter struct {
+	b cipher.Block
+}
+
+func NewECBDecrypter(b cipher.Block) *ECBDecrypter {
+	return &ECBDecrypter{b}
+}
+
+func (d *ECBDecrypter) CryptBlocks(dst, src []byte) {
+	bs := d.b.BlockSize()
+	checkBlocks(bs, dst, src)
+	for i := 0; i < len(src); i += bs {
+		d.b.Decrypt(dst[i:i+bs], src[i:i+bs])
+	}
+}
+
+func (d *ECBDecrypter) BlockSize() int {
+	return d.b.BlockSize()
+}
@@ -0,0 +1,92 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestECBInterface(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	var _ cipher.BlockMode = NewECBEncrypter(c)
+	var _ cipher.BlockMode = NewECBDecrypter(c)
+}
+
+func TestECBKuznechik(t *testing.T) {
+	ct := hexDecode("" +
+		"7f679d90bebc24305a468d42b9d4edcd" +
+		"b429912c6e0032f9285452d76718d08b" +
+		"f0ca33549d247ceef3f5a5313bd4b157" +
+		"d0b09ccde830b9eb3a02c4c5aa8ada98",
+	)
+	c := gost3412.NewCipher(kuzKey)
+	tmp := make([]byte, len(kuzPT))
+	NewECBEncrypter(c).CryptBlocks(tmp, kuzPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	NewECBDecrypter(c).CryptBlocks(tmp, tmp)
+	if bytes.Compare(tmp, kuzPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestECBMagma(t *testing.T) {
+	ct := hexDecode("" +
+		"2b073f0494f372a0" +
+		"de70e715d3556e48" +
+		"11d8d9e9eacfbc1e" +
+		"7c68260996c67efb",
+	)
+	c := gost341264.NewCipher(magmaKey)
+	tmp := make([]byte, len(magmaPT))
+	NewECBEncrypter(c).CryptBlocks(tmp, magmaPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	NewECBDecrypter(c).CryptBlocks(tmp, tmp)
+	if bytes.Compare(tmp, magmaPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func mustPanic(t *testing.T, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+	f()
+}
+
+func testPartialBlocks(t *testing.T, m cipher.BlockMode) {
+	bs := m.BlockSize()
+	mustPanic(t, func() { m.CryptBlocks(make([]byte, 2*bs), make([]byte, bs+1)) })
+	mustPanic(t, func() { m.CryptBlocks(make([]byte, bs), make([]byte, 2*bs)) })
+}
+
+func TestECBPartialBlocks(t *testing.T) {
+	c := gost341264.NewCipher(magmaKey)
+	testPartialBlocks(t, NewECBEncrypter(c))
+	testPartialBlocks(t, NewECBDecrypter(c))
+}
@@ -0,0 +1,72 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+)
+
+// KExp15 key export algorithm (R 1323565.1.017-2018). Key is
+// authenticated with OMAC over IV|key using macCipher and then
+// encrypted together with the tag in CTR mode using encCipher. IV must
+// be half of the block size long. Both ciphers must have the same
+// block size.
+func KExp15(encCipher, macCipher cipher.Block, iv, key []byte) ([]byte, error) {
+	if encCipher.BlockSize() != macCipher.BlockSize() {
+		return nil, errors.New("Different block sizes")
+	}
+	ctr, err := NewCTR(encCipher, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac, _ := NewMAC(macCipher, macCipher.BlockSize())
+	mac.Write(iv)
+	mac.Write(key)
+	out := make([]byte, 0, len(key)+mac.Size())
+	out = mac.Sum(append(out, key...))
+	ctr.XORKeyStream(out, out)
+	return out, nil
+}
+
+// KImp15 key import algorithm (R 1323565.1.017-2018), inverse of
+// KExp15. Error is returned if the tag does not match.
+func KImp15(encCipher, macCipher cipher.Block, iv, kexp []byte) ([]byte, error) {
+	if encCipher.BlockSize() != macCipher.BlockSize() {
+		return nil, errors.New("Different block sizes")
+	}
+	bs := macCipher.BlockSize()
+	if len(kexp) <= bs {
+		return nil, errors.New("Invalid exported key length")
+	}
+	ctr, err := NewCTR(encCipher, iv)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(kexp))
+	ctr.XORKeyStream(out, kexp)
+	key, tag := out[:len(out)-bs], out[len(out)-bs:]
+	mac, _ := NewMAC(macCipher, bs)
+	mac.Write(iv)
+	mac.Write(key)
+	if subtle.ConstantTimeCompare(mac.Sum(nil), tag) != 1 {
+		return nil, errors.New("Invalid key MAC")
+	}
+	return key, nil
+}
@@ -0,0 +1,122 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+var (
+	kexpKeyMAC [32]byte = [32]byte{
+		0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f,
+	}
+	kexpKeyEnc [32]byte = [32]byte{
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27,
+		0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f,
+		0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f,
+		0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37,
+	}
+)
+
+func testKExp15(t *testing.T, enc, mac cipher.Block, iv, kexp []byte) {
+	out, err := KExp15(enc, mac, iv, kuzKey[:])
+	if err != nil {
+		t.FailNow()
+	}
+	if bytes.Compare(out, kexp) != 0 {
+		t.FailNow()
+	}
+	key, err := KImp15(enc, mac, iv, out)
+	if err != nil {
+		t.FailNow()
+	}
+	if bytes.Compare(key, kuzKey[:]) != 0 {
+		t.FailNow()
+	}
+}
+
+// Test vectors taken from R 1323565.1.017-2018 Appendix A
+func TestKExp15Magma(t *testing.T) {
+	testKExp15(
+		t,
+		gost341264.NewCipher(kexpKeyEnc),
+		gost341264.NewCipher(kexpKeyMAC),
+		hexDecode("67bed654"),
+		hexDecode(""+
+			"cfd5a12d5b81b6e1e99c916d07900c6a"+
+			"c12703fb3abded55567bf3742c899c75"+
+			"5dafe7b42e3a8bd9",
+		),
+	)
+}
+
+func TestKExp15Kuznechik(t *testing.T) {
+	testKExp15(
+		t,
+		gost3412.NewCipher(kexpKeyEnc),
+		gost3412.NewCipher(kexpKeyMAC),
+		hexDecode("0909472dd9f26be8"),
+		hexDecode(""+
+			"e36184e84e8d736ff36cc2e5ae065dc6"+
+			"56b23c20f549b02fdff88e1f3f30d8c2"+
+			"9a53f3ca554dbad80de152b9a4625b32",
+		),
+	)
+}
+
+func TestKImp15Corrupted(t *testing.T) {
+	enc := gost3412.NewCipher(kexpKeyEnc)
+	mac := gost3412.NewCipher(kexpKeyMAC)
+	iv := make([]byte, 8)
+	f := func(key []byte, i uint8) bool {
+		kexp, _ := KExp15(enc, mac, iv, key)
+		kexp[int(i)%len(kexp)] ^= 0x01
+		_, err := KImp15(enc, mac, iv, kexp)
+		return err != nil
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := KImp15(enc, mac, iv, make([]byte, 16)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestKExp15DifferentBlockSizes(t *testing.T) {
+	var key [32]byte
+	rand.Read(key[:])
+	_, err := KExp15(
+		gost3412.NewCipher(kexpKeyEnc),
+		gost341264.NewCipher(kexpKeyMAC),
+		make([]byte, 8),
+		key[:],
+	)
+	if err == nil {
+		t.FailNow()
+	}
+}
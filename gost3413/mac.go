@@ -0,0 +1,127 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+const (
+	rb64  = 0x1B
+	rb128 = 0x87
+)
+
+// OMAC (CMAC) message authentication code.
+type MAC struct {
+	b    cipher.Block
+	size int
+	k1   []byte
+	k2   []byte
+	prev []byte
+	buf  []byte
+}
+
+// Derive the next OMAC subkey (K1 from E_K(0), K2 from K1): shift
+// block left by one bit and add the Rb constant if the most
+// significant bit was set. Only 64 and 128-bit blocks are supported.
+func ShiftSubkey(dst, src []byte) {
+	rb := byte(rb64)
+	if len(src) == 16 {
+		rb = rb128
+	}
+	msb := src[0] & 0x80
+	for i := 0; i < len(src)-1; i++ {
+		dst[i] = src[i]<<1 | src[i+1]>>7
+	}
+	dst[len(src)-1] = src[len(src)-1] << 1
+	if msb > 0 {
+		dst[len(src)-1] ^= rb
+	}
+}
+
+// Create MAC with given tag size (in bytes) over the 64-bit (Magma) or
+// 128-bit (Kuznechik) block cipher. Size must be between 1 and the
+// block size.
+func NewMAC(b cipher.Block, size int) (*MAC, error) {
+	bs := b.BlockSize()
+	if bs != 8 && bs != 16 {
+		return nil, errors.New("Unsupported block size")
+	}
+	if size <= 0 || size > bs {
+		return nil, errors.New("Invalid tag size")
+	}
+	m := MAC{
+		b:    b,
+		size: size,
+		k1:   make([]byte, bs),
+		k2:   make([]byte, bs),
+		prev: make([]byte, bs),
+	}
+	r := make([]byte, bs)
+	b.Encrypt(r, r)
+	ShiftSubkey(m.k1, r)
+	ShiftSubkey(m.k2, m.k1)
+	return &m, nil
+}
+
+func (m *MAC) Reset() {
+	for i := 0; i < len(m.prev); i++ {
+		m.prev[i] = 0
+	}
+	m.buf = nil
+}
+
+func (m *MAC) BlockSize() int {
+	return m.b.BlockSize()
+}
+
+func (m *MAC) Size() int {
+	return m.size
+}
+
+func (m *MAC) Write(b []byte) (int, error) {
+	bs := len(m.prev)
+	m.buf = append(m.buf, b...)
+	// The last block is kept in the buffer until Sum, because it is
+	// processed differently
+	for len(m.buf) > bs {
+		for i := 0; i < bs; i++ {
+			m.prev[i] ^= m.buf[i]
+		}
+		m.b.Encrypt(m.prev, m.prev)
+		m.buf = m.buf[bs:]
+	}
+	return len(b), nil
+}
+
+func (m *MAC) Sum(b []byte) []byte {
+	bs := len(m.prev)
+	buf := make([]byte, bs)
+	copy(buf, m.buf)
+	k := m.k1
+	if len(m.buf) != bs {
+		buf[len(m.buf)] = 0x80
+		k = m.k2
+	}
+	for i := 0; i < bs; i++ {
+		buf[i] ^= m.prev[i] ^ k[i]
+	}
+	m.b.Encrypt(buf, buf)
+	return append(b, buf[:m.size]...)
+}
@@ -0,0 +1,99 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/rand"
+	"hash"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestMACInterface(t *testing.T) {
+	m, _ := NewMAC(gost3412.NewCipher(kuzKey), 8)
+	var _ hash.Hash = m
+}
+
+func TestMACKuznechik(t *testing.T) {
+	m, err := NewMAC(gost3412.NewCipher(kuzKey), 8)
+	if err != nil {
+		t.FailNow()
+	}
+	m.Write(kuzPT)
+	if bytes.Compare(m.Sum(nil), hexDecode("336f4d296059fbe3")) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestMACMagma(t *testing.T) {
+	m, err := NewMAC(gost341264.NewCipher(magmaKey), 4)
+	if err != nil {
+		t.FailNow()
+	}
+	m.Write(magmaPT)
+	if bytes.Compare(m.Sum(nil), hexDecode("154e7210")) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestMACInvalidSize(t *testing.T) {
+	c := gost341264.NewCipher(magmaKey)
+	if _, err := NewMAC(c, 0); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewMAC(c, gost341264.BlockSize+1); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestMACRandom(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	c := gost3412.NewCipher(key)
+	m, _ := NewMAC(c, gost3412.BlockSize)
+	f := func(data []byte) bool {
+		m.Reset()
+		m.Write(data)
+		tag1 := m.Sum(nil)
+		m.Reset()
+		for _, b := range data {
+			m.Write([]byte{b})
+		}
+		return bytes.Compare(tag1, m.Sum(nil)) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkMAC(b *testing.B) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	m, _ := NewMAC(gost3412.NewCipher(key), gost3412.BlockSize)
+	data := make([]byte, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Write(data)
+		m.Sum(nil)
+		m.Reset()
+	}
+}
@@ -0,0 +1,56 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+type OFB struct {
+	b     cipher.Block
+	r     []byte
+	gamma []byte
+	used  int
+}
+
+// Output feedback mode of operation. IV is the initial value of the
+// shift register, that must be z blocks long (z >= 1).
+func NewOFB(b cipher.Block, iv []byte) (*OFB, error) {
+	bs := b.BlockSize()
+	if len(iv) == 0 || len(iv)%bs != 0 {
+		return nil, errors.New("Invalid IV size")
+	}
+	r := make([]byte, len(iv))
+	copy(r, iv)
+	return &OFB{b: b, r: r, gamma: make([]byte, bs), used: bs}, nil
+}
+
+func (o *OFB) XORKeyStream(dst, src []byte) {
+	bs := len(o.gamma)
+	for i := 0; i < len(src); i++ {
+		if o.used == bs {
+			o.b.Encrypt(o.gamma, o.r[:bs])
+			copy(o.r, o.r[bs:])
+			copy(o.r[len(o.r)-bs:], o.gamma)
+			o.used = 0
+		}
+		dst[i] = src[i] ^ o.gamma[o.used]
+		o.used++
+	}
+}
@@ -0,0 +1,77 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func TestOFBInterface(t *testing.T) {
+	c := gost3412.NewCipher(kuzKey)
+	ofb, _ := NewOFB(c, kuzIV)
+	var _ cipher.Stream = ofb
+}
+
+func TestOFBKuznechik(t *testing.T) {
+	ct := hexDecode("" +
+		"81800a59b1842b24ff1f795e897abd95" +
+		"ed5b47a7048cfab48fb521369d9326bf" +
+		"66a257ac3ca0b8b1c80fe7fc10288a13" +
+		"203ebbc066138660a0292243f6903150",
+	)
+	c := gost3412.NewCipher(kuzKey)
+	ofb, err := NewOFB(c, kuzIV)
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(kuzPT))
+	ofb.XORKeyStream(tmp, kuzPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+	ofb, _ = NewOFB(c, kuzIV)
+	ofb.XORKeyStream(tmp[:5], tmp[:5])
+	ofb.XORKeyStream(tmp[5:], tmp[5:])
+	if bytes.Compare(tmp, kuzPT) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestOFBMagma(t *testing.T) {
+	ct := hexDecode("" +
+		"db37e0e266903c83" +
+		"0d46644c1f9a089c" +
+		"a0f83062430e327e" +
+		"c824efb8bd4fdb05",
+	)
+	c := gost341264.NewCipher(magmaKey)
+	ofb, err := NewOFB(c, magmaIV[:2*gost341264.BlockSize])
+	if err != nil {
+		t.FailNow()
+	}
+	tmp := make([]byte, len(magmaPT))
+	ofb.XORKeyStream(tmp, magmaPT)
+	if bytes.Compare(tmp, ct) != 0 {
+		t.FailNow()
+	}
+}
@@ -15,9 +15,14 @@
 // License along with this program.  If not, see
 // <http://www.gnu.org/licenses/>.
 
-// GOST R 34.13-2015 padding methods.
+// GOST R 34.13-2015 padding methods and modes of operation.
 package gost3413
 
+import (
+	"crypto/subtle"
+	"errors"
+)
+
 func PadSize(dataSize, blockSize int) int {
 	if dataSize < blockSize {
 		return blockSize - dataSize
@@ -48,3 +53,73 @@ func Pad3(data []byte, blockSize int) []byte {
 	}
 	return Pad2(data, blockSize)
 }
+
+// Remove padding made by Pad2. Data length must be a non-zero multiple
+// of the block size and the padding must reside in the last block.
+func Unpad2(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("Invalid data length")
+	}
+	for i := len(data) - 1; i >= len(data)-blockSize; i-- {
+		switch data[i] {
+		case 0x00:
+			continue
+		case 0x80:
+			return data[:i], nil
+		default:
+			return nil, errors.New("Invalid padding")
+		}
+	}
+	return nil, errors.New("Invalid padding")
+}
+
+// The same as Unpad2, but the time it takes does not depend on the
+// padding contents, only on the block size. Useful when the padding
+// of decrypted unauthenticated data is checked.
+func Unpad2ConstantTime(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("Invalid data length")
+	}
+	last := data[len(data)-blockSize:]
+	var found, bad, pos, marker, zero int
+	for i := blockSize - 1; i >= 0; i-- {
+		marker = subtle.ConstantTimeByteEq(last[i], 0x80)
+		zero = subtle.ConstantTimeByteEq(last[i], 0x00)
+		bad |= (1 ^ found) & (1 ^ (marker | zero))
+		pos = subtle.ConstantTimeSelect((1^found)&marker, i, pos)
+		found |= marker
+	}
+	if bad|(1^found) != 0 {
+		return nil, errors.New("Invalid padding")
+	}
+	return data[:len(data)-blockSize+pos], nil
+}
+
+// Remove padding made by Pad3. Procedure 3 does not pad already
+// aligned data, so padding can not be distinguished from the data
+// itself: original data length must be known. Data length must match
+// Pad3 output for it and the padding is checked strictly.
+func Unpad3(data []byte, blockSize, dataSize int) ([]byte, error) {
+	if dataSize < 0 || len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("Invalid data length")
+	}
+	padSize := PadSize(dataSize, blockSize)
+	if padSize == 0 {
+		if len(data) != dataSize {
+			return nil, errors.New("Invalid data length")
+		}
+		return data, nil
+	}
+	if len(data) != dataSize+padSize {
+		return nil, errors.New("Invalid data length")
+	}
+	var bad byte
+	bad = data[dataSize] ^ 0x80
+	for _, b := range data[dataSize+1:] {
+		bad |= b
+	}
+	if bad != 0 {
+		return nil, errors.New("Invalid padding")
+	}
+	return data[:dataSize], nil
+}
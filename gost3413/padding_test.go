@@ -0,0 +1,93 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+func TestUnpad2Symmetric(t *testing.T) {
+	f := func(data []byte, bs uint8) bool {
+		blockSize := 1 + int(bs)%16
+		padded := Pad2(append([]byte{}, data...), blockSize)
+		unpadded, err := Unpad2(padded, blockSize)
+		if err != nil || bytes.Compare(unpadded, data) != 0 {
+			return false
+		}
+		unpadded, err = Unpad2ConstantTime(padded, blockSize)
+		return err == nil && bytes.Compare(unpadded, data) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnpad2Invalid(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{0x80, 0x00, 0x00},
+		{0x00, 0x00, 0x00, 0x00},
+		{0x01, 0x02, 0x80, 0x01},
+		{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	} {
+		if _, err := Unpad2(data, 4); err == nil {
+			t.FailNow()
+		}
+		if _, err := Unpad2ConstantTime(data, 4); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestUnpad3(t *testing.T) {
+	f := func(data []byte) bool {
+		padded := Pad3(append([]byte{}, data...), 8)
+		unpadded, err := Unpad3(padded, 8, len(data))
+		return err == nil && bytes.Compare(unpadded, data) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	// Aligned data ending with padding-like bytes is kept intact
+	data := []byte{0x01, 0x02, 0x80, 0x00}
+	unpadded, err := Unpad3(data, 4, 4)
+	if err != nil || bytes.Compare(unpadded, data) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestUnpad3Invalid(t *testing.T) {
+	for _, c := range []struct {
+		data     []byte
+		dataSize int
+	}{
+		{[]byte{}, 0},
+		{[]byte{0x01, 0x02, 0x03, 0x04}, 3},
+		{[]byte{0x01, 0x80, 0x00, 0x01}, 1},
+		{[]byte{0x01, 0x00, 0x00, 0x00}, 1},
+		{[]byte{0x01, 0x80, 0x00, 0x00}, 5},
+		{[]byte{0x01, 0x80, 0x00, 0x00}, -1},
+		{[]byte{0x01, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, 1},
+	} {
+		if _, err := Unpad3(c.data, 4, c.dataSize); err == nil {
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"encoding/hex"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+// Test vectors taken from GOST R 34.13-2015 Appendix A
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var (
+	kuzKey [gost3412.KeySize]byte = [gost3412.KeySize]byte{
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+	}
+	kuzPT []byte = hexDecode("" +
+		"1122334455667700ffeeddccbbaa9988" +
+		"00112233445566778899aabbcceeff0a" +
+		"112233445566778899aabbcceeff0a00" +
+		"2233445566778899aabbcceeff0a0011",
+	)
+	kuzIV []byte = hexDecode("" +
+		"1234567890abcef0a1b2c3d4e5f00112" +
+		"23344556677889901213141516171819",
+	)
+
+	magmaKey [gost341264.KeySize]byte = [gost341264.KeySize]byte{
+		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
+		0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11, 0x00,
+		0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7,
+		0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff,
+	}
+	magmaPT []byte = hexDecode("" +
+		"92def06b3c130a59" +
+		"db54c704f8189d20" +
+		"4a98fb2e67a8024c" +
+		"8912409b17b57e41",
+	)
+	magmaIV []byte = hexDecode("" +
+		"1234567890abcdef" +
+		"234567890abcdef1" +
+		"34567890abcdef12",
+	)
+)
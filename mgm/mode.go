@@ -0,0 +1,274 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Multilinear Galois Mode (MGM) block cipher mode of operation.
+// RFC 9058.
+package mgm
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// MGM keeps no state between calls and can be used concurrently.
+type MGM struct {
+	cipher    cipher.Block
+	blockSize int
+	tagSize   int
+	mul       func(dst, x, y []byte)
+}
+
+// Scratch buffers of a single Seal or Open call.
+type buffers struct {
+	icn    []byte
+	bufP   []byte
+	bufC   []byte
+	padded []byte
+	sum    []byte
+	x      []byte
+	y      []byte
+	z      []byte
+}
+
+func newBuffers(blockSize int, nonce []byte) *buffers {
+	buf := make([]byte, 8*blockSize)
+	b := buffers{
+		icn:    buf[0*blockSize : 1*blockSize],
+		bufP:   buf[1*blockSize : 2*blockSize],
+		bufC:   buf[2*blockSize : 3*blockSize],
+		padded: buf[3*blockSize : 4*blockSize],
+		sum:    buf[4*blockSize : 5*blockSize],
+		x:      buf[5*blockSize : 6*blockSize],
+		y:      buf[6*blockSize : 7*blockSize],
+		z:      buf[7*blockSize : 8*blockSize],
+	}
+	copy(b.icn, nonce)
+	return &b
+}
+
+// Create MGM AEAD over the 64-bit (Magma) or 128-bit (Kuznechik) block
+// cipher. Tag size is in bytes and must be between 4 and the block
+// size.
+func NewMGM(cipher cipher.Block, tagSize int) (*MGM, error) {
+	blockSize := cipher.BlockSize()
+	if !(blockSize == 8 || blockSize == 16) {
+		return nil, errors.New("MGM supports only 64/128 blocksizes")
+	}
+	if tagSize < 4 || tagSize > blockSize {
+		return nil, errors.New("Invalid tag size")
+	}
+	mgm := MGM{
+		cipher:    cipher,
+		blockSize: blockSize,
+		tagSize:   tagSize,
+	}
+	if blockSize == 8 {
+		mgm.mul = gfMul64
+	} else {
+		mgm.mul = gfMul128
+	}
+	return &mgm, nil
+}
+
+func (mgm *MGM) NonceSize() int {
+	return mgm.blockSize
+}
+
+func (mgm *MGM) Overhead() int {
+	return mgm.tagSize
+}
+
+// Increment the right (least significant) half of the block.
+func incrR(data []byte) {
+	for i := len(data) - 1; i >= len(data)/2; i-- {
+		data[i]++
+		if data[i] != 0 {
+			break
+		}
+	}
+}
+
+// Increment the left (most significant) half of the block.
+func incrL(data []byte) {
+	for i := len(data)/2 - 1; i >= 0; i-- {
+		data[i]++
+		if data[i] != 0 {
+			break
+		}
+	}
+}
+
+func xor(dst, src1, src2 []byte) {
+	for i := 0; i < len(src1); i++ {
+		dst[i] = src1[i] ^ src2[i]
+	}
+}
+
+// Wrong nonce length is a programming error and panics. Nonce with the
+// higher bit set may come from the wire, so it is an error.
+func (mgm *MGM) validateNonce(nonce []byte) error {
+	if len(nonce) != mgm.blockSize {
+		panic("nonce length must be equal to cipher's blocksize")
+	}
+	if nonce[0]&0x80 > 0 {
+		return errors.New("nonce must not have higher bit set")
+	}
+	return nil
+}
+
+func (mgm *MGM) validateSizes(text, additionalData []byte) error {
+	if len(text) == 0 && len(additionalData) == 0 {
+		return errors.New("at least either *text or additionalData must be provided")
+	}
+	// Lengths in bits must fit into the half of the block
+	maxSize := uint64(1) << uint(mgm.blockSize*4-3)
+	if uint64(len(text))+uint64(len(additionalData)) >= maxSize {
+		return errors.New("*text with additionalData are too big")
+	}
+	return nil
+}
+
+// Authenticate additional data and ciphertext, producing the tag.
+func (mgm *MGM) auth(b *buffers, out, text, ad []byte) {
+	for i := 0; i < mgm.blockSize; i++ {
+		b.sum[i] = 0
+	}
+	adLen := len(ad) * 8
+	textLen := len(text) * 8
+	b.icn[0] |= 0x80
+	mgm.cipher.Encrypt(b.z, b.icn) // Z_1 = E_K(1 || ICN)
+	for len(ad) >= mgm.blockSize {
+		mgm.cipher.Encrypt(b.bufC, b.z) // H_i = E_K(Z_i)
+		mgm.mul(b.x, b.bufC, ad[:mgm.blockSize])
+		xor(b.sum, b.sum, b.x)
+		incrL(b.z) // Z_{i+1} = incr_l(Z_i)
+		ad = ad[mgm.blockSize:]
+	}
+	if len(ad) > 0 {
+		copy(b.padded, ad)
+		for i := len(ad); i < mgm.blockSize; i++ {
+			b.padded[i] = 0
+		}
+		mgm.cipher.Encrypt(b.bufC, b.z)
+		mgm.mul(b.x, b.bufC, b.padded)
+		xor(b.sum, b.sum, b.x)
+		incrL(b.z)
+	}
+
+	for len(text) >= mgm.blockSize {
+		mgm.cipher.Encrypt(b.bufC, b.z) // H_{h+j} = E_K(Z_{h+j})
+		mgm.mul(b.x, b.bufC, text[:mgm.blockSize])
+		xor(b.sum, b.sum, b.x)
+		incrL(b.z) // Z_{h+j+1} = incr_l(Z_{h+j})
+		text = text[mgm.blockSize:]
+	}
+	if len(text) > 0 {
+		copy(b.padded, text)
+		for i := len(text); i < mgm.blockSize; i++ {
+			b.padded[i] = 0
+		}
+		mgm.cipher.Encrypt(b.bufC, b.z)
+		mgm.mul(b.x, b.bufC, b.padded)
+		xor(b.sum, b.sum, b.x)
+		incrL(b.z)
+	}
+
+	mgm.cipher.Encrypt(b.bufP, b.z) // H_{h+q+1} = E_K(Z_{h+q+1})
+	// len(A) || len(C)
+	if mgm.blockSize == 8 {
+		binary.BigEndian.PutUint32(b.bufC, uint32(adLen))
+		binary.BigEndian.PutUint32(b.bufC[mgm.blockSize/2:], uint32(textLen))
+	} else {
+		binary.BigEndian.PutUint64(b.bufC, uint64(adLen))
+		binary.BigEndian.PutUint64(b.bufC[mgm.blockSize/2:], uint64(textLen))
+	}
+	mgm.mul(b.x, b.bufP, b.bufC)
+	xor(b.sum, b.sum, b.x)
+	mgm.cipher.Encrypt(b.bufP, b.sum) // E_K(sum)
+	copy(out, b.bufP[:mgm.tagSize])   // MSB_S(E_K(sum))
+}
+
+// Encrypt or decrypt the text with the counter starting from
+// Y_1 = E_K(0 || ICN).
+func (mgm *MGM) crypt(b *buffers, out, in []byte) {
+	b.icn[0] &= 0x7F
+	mgm.cipher.Encrypt(b.y, b.icn) // Y_1 = E_K(0 || ICN)
+	for len(in) >= mgm.blockSize {
+		mgm.cipher.Encrypt(b.bufC, b.y)
+		xor(out, b.bufC, in)
+		incrR(b.y) // Y_{i+1} = incr_r(Y_i)
+		out = out[mgm.blockSize:]
+		in = in[mgm.blockSize:]
+	}
+	if len(in) > 0 {
+		mgm.cipher.Encrypt(b.bufC, b.y)
+		xor(out, in, b.bufC)
+	}
+}
+
+func (mgm *MGM) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if err := mgm.validateNonce(nonce); err != nil {
+		panic(err.Error())
+	}
+	if err := mgm.validateSizes(plaintext, additionalData); err != nil {
+		panic(err.Error())
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+mgm.tagSize)
+	b := newBuffers(mgm.blockSize, nonce)
+	mgm.crypt(b, out, plaintext)
+	mgm.auth(b, out[len(plaintext):], out[:len(plaintext)], additionalData)
+	return ret
+}
+
+func (mgm *MGM) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if err := mgm.validateNonce(nonce); err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < mgm.tagSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+	ct := ciphertext[:len(ciphertext)-mgm.tagSize]
+	if err := mgm.validateSizes(ct, additionalData); err != nil {
+		return nil, err
+	}
+	expectedTag := make([]byte, mgm.tagSize)
+	b := newBuffers(mgm.blockSize, nonce)
+	mgm.auth(b, expectedTag, ct, additionalData)
+	if subtle.ConstantTimeCompare(
+		expectedTag,
+		ciphertext[len(ciphertext)-mgm.tagSize:],
+	) != 1 {
+		return nil, errors.New("invalid authentication tag")
+	}
+	ret, out := sliceForAppend(dst, len(ct))
+	mgm.crypt(b, out, ct)
+	return ret, nil
+}
+
+// Taken from crypto/cipher (gcm.go).
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
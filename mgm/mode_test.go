@@ -0,0 +1,259 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"testing/quick"
+
+	"github.com/martinlindhe/gogost/gost3412"
+	"github.com/martinlindhe/gogost/gost341264"
+)
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestAEADInterface(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	aead, _ := NewMGM(gost3412.NewCipher(key), gost3412.BlockSize)
+	var _ cipher.AEAD = aead
+}
+
+// Test vectors taken from RFC 9058 Appendix A
+func TestVectorKuznechik(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	copy(key[:], hexDecode(""+
+		"8899aabbccddeeff0011223344556677"+
+		"fedcba98765432100123456789abcdef",
+	))
+	nonce := hexDecode("1122334455667700ffeeddccbbaa9988")
+	ad := hexDecode("" +
+		"02020202020202020101010101010101" +
+		"04040404040404040303030303030303" +
+		"ea0505050505050505",
+	)
+	pt := hexDecode("" +
+		"1122334455667700ffeeddccbbaa9988" +
+		"00112233445566778899aabbcceeff0a" +
+		"112233445566778899aabbcceeff0a00" +
+		"2233445566778899aabbcceeff0a0011" +
+		"aabbcc",
+	)
+	ct := hexDecode("" +
+		"a9757b8147956e9055b8a33de89f42fc" +
+		"8075d2212bf9fd5bd3f7069aadc16b39" +
+		"497ab15915a6ba85936b5d0ea9f6851c" +
+		"c60c14d4d3f883d0ab94420695c76deb" +
+		"2c7552",
+	)
+	tag := hexDecode("cf5d656f40c34f5c46e8bb0e29fcdb4c")
+
+	aead, err := NewMGM(gost3412.NewCipher(key), gost3412.BlockSize)
+	if err != nil {
+		t.FailNow()
+	}
+	sealed := aead.Seal(nil, nonce, pt, ad)
+	if bytes.Compare(sealed[:len(pt)], ct) != 0 {
+		t.FailNow()
+	}
+	if bytes.Compare(sealed[len(pt):], tag) != 0 {
+		t.FailNow()
+	}
+	opened, err := aead.Open(nil, nonce, sealed, ad)
+	if err != nil {
+		t.FailNow()
+	}
+	if bytes.Compare(opened, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestVectorMagma(t *testing.T) {
+	var key [gost341264.KeySize]byte
+	copy(key[:], hexDecode(""+
+		"ffeeddccbbaa99887766554433221100"+
+		"f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+	))
+	nonce := hexDecode("12def06b3c130a59")
+	ad := hexDecode("" +
+		"0101010101010101" +
+		"0202020202020202" +
+		"0303030303030303" +
+		"0404040404040404" +
+		"0505050505050505" +
+		"ea",
+	)
+	pt := hexDecode("" +
+		"ffeeddccbbaa9988" +
+		"1122334455667700" +
+		"8899aabbcceeff0a" +
+		"0011223344556677" +
+		"99aabbcceeff0a00" +
+		"1122334455667788" +
+		"aabbcceeff0a0011" +
+		"2233445566778899" +
+		"aabbcc",
+	)
+	ct := hexDecode("" +
+		"c795066c5f9ea03b" +
+		"85113342459185ae" +
+		"1f2e00d6bf2b785d" +
+		"940470b8bb9c8e7d" +
+		"9a5dd3731f7ddc70" +
+		"ec27cb0ace6fa576" +
+		"70f65c646abb75d5" +
+		"47aa37c3bcb5c34e" +
+		"03bb9c",
+	)
+	tag := hexDecode("a7928069aa10fd10")
+
+	aead, err := NewMGM(gost341264.NewCipher(key), gost341264.BlockSize)
+	if err != nil {
+		t.FailNow()
+	}
+	sealed := aead.Seal(nil, nonce, pt, ad)
+	if bytes.Compare(sealed[:len(pt)], ct) != 0 {
+		t.FailNow()
+	}
+	if bytes.Compare(sealed[len(pt):], tag) != 0 {
+		t.FailNow()
+	}
+	opened, err := aead.Open(nil, nonce, sealed, ad)
+	if err != nil {
+		t.FailNow()
+	}
+	if bytes.Compare(opened, pt) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestRandom(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	aead, _ := NewMGM(gost3412.NewCipher(key), 12)
+	f := func(nonce [gost3412.BlockSize]byte, pt, ad []byte) bool {
+		if len(pt) == 0 && len(ad) == 0 {
+			return true
+		}
+		nonce[0] &= 0x7F
+		sealed := aead.Seal(nil, nonce[:], pt, ad)
+		opened, err := aead.Open(nil, nonce[:], sealed, ad)
+		if err != nil || bytes.Compare(opened, pt) != 0 {
+			return false
+		}
+		sealed[len(sealed)-1] ^= 0x01
+		if _, err = aead.Open(nil, nonce[:], sealed, ad); err == nil {
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNonceMSB(t *testing.T) {
+	var key [gost341264.KeySize]byte
+	aead, _ := NewMGM(gost341264.NewCipher(key), gost341264.BlockSize)
+	nonce := make([]byte, gost341264.BlockSize)
+	nonce[0] = 0x80
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+	if _, err := aead.Open(nil, nonce, make([]byte, 2*gost341264.BlockSize), nil); err == nil {
+		t.FailNow()
+	}
+	aead.Seal(nil, nonce, []byte("data"), nil)
+}
+
+func TestConcurrent(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	aead, _ := NewMGM(gost3412.NewCipher(key), gost3412.BlockSize)
+	nonce := make([]byte, gost3412.BlockSize)
+	pts := make([][]byte, 4)
+	expected := make([][]byte, len(pts))
+	for i := range pts {
+		pts[i] = make([]byte, 100+i)
+		rand.Read(pts[i])
+		expected[i] = aead.Seal(nil, nonce, pts[i], nil)
+	}
+	results := make(chan bool, len(pts))
+	for i := range pts {
+		go func(i int) {
+			ok := true
+			for n := 0; n < 10; n++ {
+				sealed := aead.Seal(nil, nonce, pts[i], nil)
+				opened, err := aead.Open(nil, nonce, sealed, nil)
+				ok = ok && err == nil &&
+					bytes.Compare(sealed, expected[i]) == 0 &&
+					bytes.Compare(opened, pts[i]) == 0
+			}
+			results <- ok
+		}(i)
+	}
+	for range pts {
+		if !<-results {
+			t.FailNow()
+		}
+	}
+}
+
+func TestOpenTagOnly(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	aead, _ := NewMGM(gost3412.NewCipher(key), gost3412.BlockSize)
+	nonce := make([]byte, gost3412.BlockSize)
+	if _, err := aead.Open(nil, nonce, make([]byte, gost3412.BlockSize), nil); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestInvalidTagSize(t *testing.T) {
+	var key [gost3412.KeySize]byte
+	c := gost3412.NewCipher(key)
+	if _, err := NewMGM(c, 3); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewMGM(c, gost3412.BlockSize+1); err == nil {
+		t.FailNow()
+	}
+}
+
+func BenchmarkMGM(b *testing.B) {
+	var key [gost3412.KeySize]byte
+	rand.Read(key[:])
+	aead, _ := NewMGM(gost3412.NewCipher(key), gost3412.BlockSize)
+	nonce := make([]byte, gost3412.BlockSize)
+	pt := make([]byte, 1024)
+	dst := make([]byte, 0, len(pt)+gost3412.BlockSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aead.Seal(dst, nonce, pt, nil)
+	}
+}
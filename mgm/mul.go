@@ -0,0 +1,61 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"encoding/binary"
+)
+
+// Multiplication in GF(2^64) with x^64 + x^4 + x^3 + x + 1 polynomial.
+// Blocks are treated as big-endian numbers.
+func gfMul64(dst, xRaw, yRaw []byte) {
+	x := binary.BigEndian.Uint64(xRaw)
+	y := binary.BigEndian.Uint64(yRaw)
+	var z, mask uint64
+	for i := 0; i < 64; i++ {
+		z ^= x & -(y & 1)
+		y >>= 1
+		mask = -(x >> 63)
+		x = (x << 1) ^ (0x1B & mask)
+	}
+	binary.BigEndian.PutUint64(dst, z)
+}
+
+// Multiplication in GF(2^128) with x^128 + x^7 + x^2 + x + 1
+// polynomial. Blocks are treated as big-endian numbers.
+func gfMul128(dst, xRaw, yRaw []byte) {
+	xHi := binary.BigEndian.Uint64(xRaw[:8])
+	xLo := binary.BigEndian.Uint64(xRaw[8:])
+	yHi := binary.BigEndian.Uint64(yRaw[:8])
+	yLo := binary.BigEndian.Uint64(yRaw[8:])
+	var zHi, zLo, bit, mask uint64
+	for i := 0; i < 128; i++ {
+		if i < 64 {
+			bit = yLo >> uint(i) & 1
+		} else {
+			bit = yHi >> uint(i-64) & 1
+		}
+		zHi ^= xHi & -bit
+		zLo ^= xLo & -bit
+		mask = -(xHi >> 63)
+		xHi = (xHi << 1) | (xLo >> 63)
+		xLo = (xLo << 1) ^ (0x87 & mask)
+	}
+	binary.BigEndian.PutUint64(dst[:8], zHi)
+	binary.BigEndian.PutUint64(dst[8:], zLo)
+}
@@ -0,0 +1,477 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Registry of ASN.1 object identifiers for GOST curves, S-boxes and
+// algorithms.
+package oid
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/gost34112012512"
+	"github.com/martinlindhe/gogost/gost341194"
+)
+
+type curve struct {
+	oid    asn1.ObjectIdentifier
+	name   string
+	params *gost3410.CurveParams
+}
+
+type sbox struct {
+	oid  asn1.ObjectIdentifier
+	name string
+	sbox *gost28147.Sbox
+}
+
+var (
+	curves []curve = []curve{
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 0},
+			"id-GostR3410-2001-TestParamSet",
+			&gost3410.CurveParamsGostR34102001Test,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 1},
+			"id-GostR3410-2001-CryptoPro-A-ParamSet",
+			&gost3410.CurveParamsGostR34102001CryptoProA,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 2},
+			"id-GostR3410-2001-CryptoPro-B-ParamSet",
+			&gost3410.CurveParamsGostR34102001CryptoProB,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 3},
+			"id-GostR3410-2001-CryptoPro-C-ParamSet",
+			&gost3410.CurveParamsGostR34102001CryptoProC,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 0},
+			"id-GostR3410-2001-CryptoPro-XchA-ParamSet",
+			&gost3410.CurveParamsGostR34102001CryptoProXchA,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 1},
+			"id-GostR3410-2001-CryptoPro-XchB-ParamSet",
+			&gost3410.CurveParamsGostR34102001CryptoProXchB,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 9, 1, 8, 1},
+			"id-GostR3410-2001-ParamSet-cc",
+			&gost3410.CurveParamsGostR34102001cc,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 1},
+			"id-tc26-gost-3410-2012-256-paramSetA",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetA256,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 2},
+			"id-tc26-gost-3410-2012-256-paramSetB",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetB256,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 3},
+			"id-tc26-gost-3410-2012-256-paramSetC",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetC256,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 4},
+			"id-tc26-gost-3410-2012-256-paramSetD",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetD256,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 1},
+			"id-tc26-gost-3410-2012-512-paramSetA",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetA,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 2},
+			"id-tc26-gost-3410-2012-512-paramSetB",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetB,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 3},
+			"id-tc26-gost-3410-2012-512-paramSetC",
+			&gost3410.CurveParamsGostR34102012TC26ParamSetC,
+		},
+	}
+
+	sboxes []sbox = []sbox{
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 30, 0},
+			"id-GostR3411-94-TestParamSet",
+			&gost28147.GostR3411_94_TestParamSet,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 30, 1},
+			"id-GostR3411-94-CryptoProParamSet",
+			&gost28147.GostR3411_94_CryptoProParamSet,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 0},
+			"id-Gost28147-89-TestParamSet",
+			&gost28147.Gost2814789_TestParamSet,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 1},
+			"id-Gost28147-89-CryptoPro-A-ParamSet",
+			&gost28147.Gost28147_CryptoProParamSetA,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 2},
+			"id-Gost28147-89-CryptoPro-B-ParamSet",
+			&gost28147.Gost28147_CryptoProParamSetB,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 3},
+			"id-Gost28147-89-CryptoPro-C-ParamSet",
+			&gost28147.Gost28147_CryptoProParamSetC,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 4},
+			"id-Gost28147-89-CryptoPro-D-ParamSet",
+			&gost28147.Gost28147_CryptoProParamSetD,
+		},
+		{
+			asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 5, 1, 1},
+			"id-tc26-gost-28147-param-Z",
+			&gost28147.Gost28147_tc26_ParamZ,
+		},
+	}
+)
+
+// Get copy of curve parameters by their OID. Nil is returned if OID is
+// unknown.
+func CurveParamsByOID(oid asn1.ObjectIdentifier) *gost3410.CurveParams {
+	for _, c := range curves {
+		if c.oid.Equal(oid) {
+			var params gost3410.CurveParams
+			for i, v := range c.params {
+				params[i] = append([]byte{}, v...)
+			}
+			return &params
+		}
+	}
+	return nil
+}
+
+func curveParamsEqual(p1, p2 *gost3410.CurveParams) bool {
+	for i := 0; i < len(p1); i++ {
+		if bytes.Compare(p1[i], p2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Get OID of the curve parameters. Curve parameters are searched by
+// pointer first and then by their contents: as some curves have several
+// OIDs, the first registered one is returned in that case. Nil is
+// returned if the curve is unknown.
+func CurveParamsOID(params *gost3410.CurveParams) asn1.ObjectIdentifier {
+	for _, c := range curves {
+		if c.params == params {
+			return c.oid
+		}
+	}
+	for _, c := range curves {
+		if curveParamsEqual(c.params, params) {
+			return c.oid
+		}
+	}
+	return nil
+}
+
+// Create curve by its parameters OID.
+func CurveByOID(oid asn1.ObjectIdentifier) (*gost3410.Curve, error) {
+	params := CurveParamsByOID(oid)
+	if params == nil {
+		return nil, errors.New("Unknown curve OID")
+	}
+	return gost3410.NewCurveFromParams(*params)
+}
+
+// Get OID of the curve, searched by its parameters. Nil is returned if
+// the curve is unknown.
+func CurveOID(c *gost3410.Curve) asn1.ObjectIdentifier {
+	for _, cp := range curves {
+		if c.P.Cmp(big.NewInt(0).SetBytes(cp.params[0])) == 0 &&
+			c.Q.Cmp(big.NewInt(0).SetBytes(cp.params[1])) == 0 &&
+			c.A.Cmp(big.NewInt(0).SetBytes(cp.params[2])) == 0 &&
+			c.B.Cmp(big.NewInt(0).SetBytes(cp.params[3])) == 0 &&
+			c.Bx.Cmp(big.NewInt(0).SetBytes(cp.params[4])) == 0 &&
+			c.By.Cmp(big.NewInt(0).SetBytes(cp.params[5])) == 0 {
+			return cp.oid
+		}
+	}
+	return nil
+}
+
+// Get S-box by its OID. Nil is returned if OID is unknown.
+func SboxByOID(oid asn1.ObjectIdentifier) *gost28147.Sbox {
+	for _, s := range sboxes {
+		if s.oid.Equal(oid) {
+			return s.sbox
+		}
+	}
+	return nil
+}
+
+// Get OID of the S-box, searched by pointer first and then by its
+// contents. Nil is returned if the S-box is unknown.
+func SboxOID(sbox *gost28147.Sbox) asn1.ObjectIdentifier {
+	for _, s := range sboxes {
+		if s.sbox == sbox {
+			return s.oid
+		}
+	}
+	for _, s := range sboxes {
+		if *s.sbox == *sbox {
+			return s.oid
+		}
+	}
+	return nil
+}
+
+// Get human readable name of any registered OID. Empty string is
+// returned if OID is unknown.
+func Name(oid asn1.ObjectIdentifier) string {
+	for _, c := range curves {
+		if c.oid.Equal(oid) {
+			return c.name
+		}
+	}
+	for _, s := range sboxes {
+		if s.oid.Equal(oid) {
+			return s.name
+		}
+	}
+	if a := AlgorithmByOID(oid); a != Unknown {
+		return a.String()
+	}
+	return ""
+}
+
+type Algorithm int
+
+const (
+	Unknown Algorithm = iota
+
+	// Digest algorithms
+	GostR341194
+	GostR34112012256
+	GostR34112012512
+
+	// HMAC algorithms
+	HMACGostR341194
+	HMACGostR34112012256
+	HMACGostR34112012512
+
+	// Public key algorithms
+	GostR34102001
+	GostR34102012256
+	GostR34102012512
+
+	// Signature algorithms
+	GostR341194WithGostR34102001
+	GostR34102012256WithGostR34112012256
+	GostR34102012512WithGostR34112012512
+
+	// Key agreement algorithms
+	GostR34102012256Agreement
+	GostR34102012512Agreement
+
+	// Encryption and key wrap algorithms
+	Gost2814789
+	Gost2814789MAC
+	Gost2814789NoneKeyWrap
+	Gost2814789CryptoProKeyWrap
+	MagmaCTRACPKM
+	MagmaCTRACPKMOMAC
+	KuznyechikCTRACPKM
+	KuznyechikCTRACPKMOMAC
+	MagmaKExp15
+	KuznyechikKExp15
+)
+
+type algorithm struct {
+	oid  asn1.ObjectIdentifier
+	name string
+}
+
+var algorithms map[Algorithm]algorithm = map[Algorithm]algorithm{
+	GostR341194: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 9},
+		"id-GostR3411-94",
+	},
+	GostR34112012256: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 2},
+		"id-tc26-gost3411-12-256",
+	},
+	GostR34112012512: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 3},
+		"id-tc26-gost3411-12-512",
+	},
+	HMACGostR341194: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 10},
+		"id-HMACGostR3411-94",
+	},
+	HMACGostR34112012256: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 4, 1},
+		"id-tc26-hmac-gost-3411-12-256",
+	},
+	HMACGostR34112012512: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 4, 2},
+		"id-tc26-hmac-gost-3411-12-512",
+	},
+	GostR34102001: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 19},
+		"id-GostR3410-2001",
+	},
+	GostR34102012256: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 1},
+		"id-tc26-gost3410-12-256",
+	},
+	GostR34102012512: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 2},
+		"id-tc26-gost3410-12-512",
+	},
+	GostR341194WithGostR34102001: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 3},
+		"id-GostR3411-94-with-GostR3410-2001",
+	},
+	GostR34102012256WithGostR34112012256: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 3, 2},
+		"id-tc26-signwithdigest-gost3410-12-256",
+	},
+	GostR34102012512WithGostR34112012512: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 3, 3},
+		"id-tc26-signwithdigest-gost3410-12-512",
+	},
+	GostR34102012256Agreement: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 6, 1},
+		"id-tc26-agreement-gost-3410-12-256",
+	},
+	GostR34102012512Agreement: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 6, 2},
+		"id-tc26-agreement-gost-3410-12-512",
+	},
+	Gost2814789: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 21},
+		"id-Gost28147-89",
+	},
+	Gost2814789MAC: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 22},
+		"id-Gost28147-89-MAC",
+	},
+	Gost2814789NoneKeyWrap: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 13, 0},
+		"id-Gost28147-89-None-KeyWrap",
+	},
+	Gost2814789CryptoProKeyWrap: {
+		asn1.ObjectIdentifier{1, 2, 643, 2, 2, 13, 1},
+		"id-Gost28147-89-CryptoPro-KeyWrap",
+	},
+	MagmaCTRACPKM: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 1, 1},
+		"id-tc26-cipher-gostr3412-2015-magma-ctracpkm",
+	},
+	MagmaCTRACPKMOMAC: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 1, 2},
+		"id-tc26-cipher-gostr3412-2015-magma-ctracpkm-omac",
+	},
+	KuznyechikCTRACPKM: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 2, 1},
+		"id-tc26-cipher-gostr3412-2015-kuznyechik-ctracpkm",
+	},
+	KuznyechikCTRACPKMOMAC: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 2, 2},
+		"id-tc26-cipher-gostr3412-2015-kuznyechik-ctracpkm-omac",
+	},
+	MagmaKExp15: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 7, 1, 1},
+		"id-tc26-wrap-gostr3412-2015-magma-kexp15",
+	},
+	KuznyechikKExp15: {
+		asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 7, 2, 1},
+		"id-tc26-wrap-gostr3412-2015-kuznyechik-kexp15",
+	},
+}
+
+// Get algorithm by its OID. Unknown is returned if OID is unknown.
+func AlgorithmByOID(oid asn1.ObjectIdentifier) Algorithm {
+	for a, desc := range algorithms {
+		if desc.oid.Equal(oid) {
+			return a
+		}
+	}
+	return Unknown
+}
+
+// Algorithm's OID. Nil is returned for Unknown.
+func (a Algorithm) OID() asn1.ObjectIdentifier {
+	return algorithms[a].oid
+}
+
+func (a Algorithm) String() string {
+	if a == Unknown {
+		return "unknown"
+	}
+	return algorithms[a].name
+}
+
+// Digest algorithm used by the signature, HMAC or public key algorithm,
+// or algorithm itself if it is a digest. Unknown is returned for
+// others.
+func (a Algorithm) Digest() Algorithm {
+	switch a {
+	case GostR341194, GostR34112012256, GostR34112012512:
+		return a
+	case HMACGostR341194, GostR34102001, GostR341194WithGostR34102001:
+		return GostR341194
+	case HMACGostR34112012256, GostR34102012256,
+		GostR34102012256WithGostR34112012256:
+		return GostR34112012256
+	case HMACGostR34112012512, GostR34102012512,
+		GostR34102012512WithGostR34112012512:
+		return GostR34112012512
+	}
+	return Unknown
+}
+
+// Create digest algorithm's hash. GOST R 34.11-94 uses CryptoPro
+// S-box. Nil is returned if algorithm has no digest.
+func (a Algorithm) NewHash() hash.Hash {
+	switch a.Digest() {
+	case GostR341194:
+		return gost341194.New(&gost28147.GostR3411_94_CryptoProParamSet)
+	case GostR34112012256:
+		return gost34112012256.New()
+	case GostR34112012512:
+		return gost34112012512.New()
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package oid
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost3410"
+)
+
+func TestCurves(t *testing.T) {
+	for _, c := range curves {
+		params := CurveParamsByOID(c.oid)
+		if params == c.params || !curveParamsEqual(params, c.params) {
+			t.FailNow()
+		}
+		if !CurveParamsOID(c.params).Equal(c.oid) {
+			t.FailNow()
+		}
+		if Name(c.oid) != c.name {
+			t.FailNow()
+		}
+		curve, err := CurveByOID(c.oid)
+		if err != nil {
+			t.FailNow()
+		}
+		if !curveParamsEqual(CurveParamsByOID(CurveOID(curve)), c.params) {
+			t.FailNow()
+		}
+	}
+	params := CurveParamsByOID(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 1})
+	oid := CurveParamsOID(params)
+	if !oid.Equal(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 1}) {
+		t.FailNow()
+	}
+	params[0][0] ^= 0xFF
+	if gost3410.CurveParamsGostR34102012TC26ParamSetA[0][0] != 0xFF {
+		t.FailNow()
+	}
+	if CurveParamsByOID(asn1.ObjectIdentifier{1, 2, 3}) != nil {
+		t.FailNow()
+	}
+}
+
+func TestSboxes(t *testing.T) {
+	for _, s := range sboxes {
+		if SboxByOID(s.oid) != s.sbox {
+			t.FailNow()
+		}
+		if !SboxOID(s.sbox).Equal(s.oid) {
+			t.FailNow()
+		}
+	}
+	oid := SboxOID(gost28147.SboxDefault)
+	if !oid.Equal(asn1.ObjectIdentifier{1, 2, 643, 2, 2, 31, 1}) {
+		t.FailNow()
+	}
+	if SboxByOID(asn1.ObjectIdentifier{1, 2, 3}) != nil {
+		t.FailNow()
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	for a, desc := range algorithms {
+		if AlgorithmByOID(desc.oid) != a {
+			t.FailNow()
+		}
+		if !a.OID().Equal(desc.oid) || a.String() != desc.name {
+			t.FailNow()
+		}
+	}
+	a := AlgorithmByOID(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 3, 2})
+	if a != GostR34102012256WithGostR34112012256 {
+		t.FailNow()
+	}
+	if a.Digest() != GostR34112012256 || a.NewHash().Size() != 32 {
+		t.FailNow()
+	}
+	h := GostR341194WithGostR34102001.NewHash()
+	h.Write([]byte("message"))
+	if bytes.Compare(h.Sum(nil), GostR341194.NewHash().Sum(nil)) == 0 {
+		t.FailNow()
+	}
+	if Gost2814789.NewHash() != nil || Unknown.OID() != nil {
+		t.FailNow()
+	}
+	if AlgorithmByOID(asn1.ObjectIdentifier{1, 2, 3}) != Unknown {
+		t.FailNow()
+	}
+}
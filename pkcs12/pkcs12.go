@@ -0,0 +1,491 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// PKCS#12 (RFC 7292) personal information exchange containers with
+// GOST protections (R 1323565.1.041-2022, RFC 9548).
+package pkcs12
+
+import (
+	"crypto/hmac"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/gost34112012256"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// PBKDF2 output length for MAC key derivation: only the last 32
+	// bytes of it are used as HMAC key.
+	macKDFSize = 96
+
+	macSaltSize = 32
+)
+
+var (
+	oidData          asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedData asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+
+	oidLocalKeyID      asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidX509Certificate asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue // [0] EXPLICIT
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue // [0] EXPLICIT
+	Attributes []attribute   `asn1:"set,optional"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data asn1.RawValue // [0] EXPLICIT
+}
+
+// Explicitly tagged value. encoding/asn1 ignores explicit tag of
+// RawValue with FullBytes, so it is made manually.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      der,
+	}
+}
+
+func unexplicit(rv asn1.RawValue) ([]byte, error) {
+	if rv.Class != asn1.ClassContextSpecific || rv.Tag != 0 || !rv.IsCompound {
+		return nil, errors.New("Unexpected tag")
+	}
+	return rv.Bytes, nil
+}
+
+// Key for HMAC-Streebog-512 over the authenticated safe: the last 32
+// bytes of 96-byte PBKDF2 output, as R 1323565.1.041-2022 requires.
+func macKey(password, salt []byte, iterations int) []byte {
+	return pbkdf2.Key(
+		password,
+		salt,
+		iterations,
+		macKDFSize,
+		oid.HMACGostR34112012512.NewHash,
+	)[macKDFSize-gost28147.KeySize:]
+}
+
+func computeMAC(password, salt []byte, iterations int, data []byte) []byte {
+	mac := hmac.New(oid.HMACGostR34112012512.NewHash, macKey(password, salt, iterations))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Options for Encode.
+type EncodeOptions struct {
+	// Encryption scheme of private key and certificates:
+	// oid.KuznyechikCTRACPKM (default), oid.MagmaCTRACPKM or
+	// oid.Gost2814789 (CFB with CryptoPro key meshing).
+	Algorithm oid.Algorithm
+
+	// Number of PBKDF2 iterations, x509.PBKDF2Iterations by default,
+	// at most x509.PBKDF2MaxIterations.
+	Iterations int
+}
+
+func marshalBag(id asn1.ObjectIdentifier, value []byte, localKeyID []byte) (safeBag, error) {
+	bag := safeBag{ID: id, Value: explicit(value)}
+	if localKeyID != nil {
+		raw, err := asn1.Marshal(localKeyID)
+		if err != nil {
+			return bag, err
+		}
+		bag.Attributes = []attribute{{oidLocalKeyID, []asn1.RawValue{{FullBytes: raw}}}}
+	}
+	return bag, nil
+}
+
+func marshalCertBag(cert *x509.Certificate, localKeyID []byte) (safeBag, error) {
+	data, err := asn1.Marshal(cert.Raw)
+	if err != nil {
+		return safeBag{}, err
+	}
+	raw, err := asn1.Marshal(certBag{oidX509Certificate, explicit(data)})
+	if err != nil {
+		return safeBag{}, err
+	}
+	return marshalBag(oidCertBag, raw, localKeyID)
+}
+
+// Create DER encoded PFX with password protected private key and
+// certificates. Private key is placed in PKCS#8 shrouded key bag,
+// certificates are placed in EncryptedData. Both are encrypted with
+// PBES2 and authenticated with HMAC-Streebog-512 MAC.
+func Encode(
+	rand io.Reader,
+	prv *gost3410.PrivateKey,
+	cert *x509.Certificate,
+	caCerts []*x509.Certificate,
+	password []byte,
+	opts *EncodeOptions,
+) ([]byte, error) {
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	algo := opts.Algorithm
+	switch algo {
+	case oid.Unknown:
+		algo = oid.KuznyechikCTRACPKM
+	case oid.KuznyechikCTRACPKM, oid.MagmaCTRACPKM, oid.Gost2814789:
+	default:
+		return nil, errors.New("Unsupported encryption algorithm")
+	}
+	iterations := opts.Iterations
+	if iterations == 0 {
+		iterations = x509.PBKDF2Iterations
+	}
+	if iterations < 0 || iterations > x509.PBKDF2MaxIterations {
+		return nil, errors.New("Invalid iterations count")
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if cert.PublicKey == nil || !pub.Equal(cert.PublicKey) {
+		return nil, errors.New("Certificate does not match private key")
+	}
+	h := gost34112012256.New()
+	h.Write(cert.Raw)
+	localKeyID := h.Sum(nil)
+
+	raw, err := x509.EncryptPKCS8PrivateKey(
+		prv,
+		cert.PublicKeyAlgorithm,
+		algo,
+		password,
+		iterations,
+		rand,
+	)
+	if err != nil {
+		return nil, err
+	}
+	keyBag, err := marshalBag(oidPKCS8ShroudedKeyBag, raw, localKeyID)
+	if err != nil {
+		return nil, err
+	}
+	keyContents, err := asn1.Marshal([]safeBag{keyBag})
+	if err != nil {
+		return nil, err
+	}
+	keyContents, err = asn1.Marshal(keyContents)
+	if err != nil {
+		return nil, err
+	}
+
+	certBags := make([]safeBag, 0, 1+len(caCerts))
+	bag, err := marshalCertBag(cert, localKeyID)
+	if err != nil {
+		return nil, err
+	}
+	certBags = append(certBags, bag)
+	for _, caCert := range caCerts {
+		if bag, err = marshalCertBag(caCert, nil); err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	certContents, err := asn1.Marshal(certBags)
+	if err != nil {
+		return nil, err
+	}
+	certAI, certEncrypted, err := x509.EncryptPBES2(algo, password, certContents, iterations, rand)
+	if err != nil {
+		return nil, err
+	}
+	certContents, err = asn1.Marshal(encryptedData{
+		Version: 0,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidData,
+			ContentEncryptionAlgorithm: certAI,
+			EncryptedContent:           certEncrypted,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authSafe, err := asn1.Marshal([]contentInfo{
+		{oidData, explicit(keyContents)},
+		{oidEncryptedData, explicit(certContents)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, macSaltSize)
+	if _, err = io.ReadFull(rand, salt); err != nil {
+		return nil, err
+	}
+	authSafeOctets, err := asn1.Marshal(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfxPdu{
+		Version:  3,
+		AuthSafe: contentInfo{oidData, explicit(authSafeOctets)},
+		MacData: macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{
+					Algorithm: oid.GostR34112012512.OID(),
+				},
+				Digest: computeMAC(password, salt, iterations, authSafe),
+			},
+			MacSalt:    salt,
+			Iterations: iterations,
+		},
+	})
+}
+
+// Unwrap OCTET STRING content of Data ContentInfo.
+func parseData(ci contentInfo) ([]byte, error) {
+	if !ci.ContentType.Equal(oidData) {
+		return nil, errors.New("Unexpected content type")
+	}
+	der, err := unexplicit(ci.Content)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	rest, err := asn1.Unmarshal(der, &data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after content")
+	}
+	return data, nil
+}
+
+func parseEncryptedData(ci contentInfo, password []byte) ([]byte, error) {
+	der, err := unexplicit(ci.Content)
+	if err != nil {
+		return nil, err
+	}
+	var ed encryptedData
+	rest, err := asn1.Unmarshal(der, &ed)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after encrypted data")
+	}
+	if !ed.EncryptedContentInfo.ContentType.Equal(oidData) {
+		return nil, errors.New("Unexpected encrypted content type")
+	}
+	return x509.DecryptPBES2(
+		ed.EncryptedContentInfo.ContentEncryptionAlgorithm,
+		password,
+		ed.EncryptedContentInfo.EncryptedContent,
+	)
+}
+
+func parseCertBag(der []byte) (*x509.Certificate, error) {
+	var bag certBag
+	if _, err := asn1.Unmarshal(der, &bag); err != nil {
+		return nil, err
+	}
+	if !bag.ID.Equal(oidX509Certificate) {
+		return nil, nil
+	}
+	data, err := unexplicit(bag.Data)
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	if _, err = asn1.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(raw)
+}
+
+// Parse DER encoded PFX, verify its HMAC-Streebog-512 MAC and decrypt
+// its contents with the password. Exactly one private key must be
+// present. Certificate corresponding to the private key is returned
+// separately from other ones and it is nil if there is no such one.
+// Bags of other types are ignored.
+func Decode(der, password []byte) (
+	prv *gost3410.PrivateKey,
+	algo oid.Algorithm,
+	cert *x509.Certificate,
+	caCerts []*x509.Certificate,
+	err error,
+) {
+	var pfx pfxPdu
+	rest, err := asn1.Unmarshal(der, &pfx)
+	if err != nil {
+		return
+	}
+	if len(rest) > 0 {
+		err = errors.New("Trailing data after PFX")
+		return
+	}
+	if pfx.Version != 3 {
+		err = errors.New("Unsupported PFX version")
+		return
+	}
+	authSafe, err := parseData(pfx.AuthSafe)
+	if err != nil {
+		return
+	}
+	if pfx.MacData.Mac.Digest == nil {
+		err = errors.New("Missing MAC data")
+		return
+	}
+	if oid.AlgorithmByOID(pfx.MacData.Mac.Algorithm.Algorithm) != oid.GostR34112012512 {
+		err = errors.New("Unsupported MAC algorithm")
+		return
+	}
+	if pfx.MacData.Iterations <= 0 || pfx.MacData.Iterations > x509.PBKDF2MaxIterations {
+		err = errors.New("Invalid MAC iterations count")
+		return
+	}
+	if !hmac.Equal(pfx.MacData.Mac.Digest, computeMAC(
+		password,
+		pfx.MacData.MacSalt,
+		pfx.MacData.Iterations,
+		authSafe,
+	)) {
+		err = errors.New("Invalid MAC or password")
+		return
+	}
+
+	var cis []contentInfo
+	if rest, err = asn1.Unmarshal(authSafe, &cis); err != nil {
+		return
+	}
+	if len(rest) > 0 {
+		err = errors.New("Trailing data after authenticated safe")
+		return
+	}
+	var certs []*x509.Certificate
+	for _, ci := range cis {
+		var contents []byte
+		switch {
+		case ci.ContentType.Equal(oidData):
+			contents, err = parseData(ci)
+		case ci.ContentType.Equal(oidEncryptedData):
+			contents, err = parseEncryptedData(ci, password)
+		default:
+			err = errors.New("Unsupported content type")
+		}
+		if err != nil {
+			return
+		}
+		var bags []safeBag
+		if _, err = asn1.Unmarshal(contents, &bags); err != nil {
+			return
+		}
+		for _, bag := range bags {
+			var value []byte
+			if value, err = unexplicit(bag.Value); err != nil {
+				return
+			}
+			switch {
+			case bag.ID.Equal(oidKeyBag), bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+				if prv != nil {
+					err = errors.New("Multiple private keys are not supported")
+					return
+				}
+				if bag.ID.Equal(oidPKCS8ShroudedKeyBag) {
+					prv, algo, err = x509.DecryptPKCS8PrivateKey(value, password)
+				} else {
+					prv, algo, err = x509.ParsePKCS8PrivateKey(value)
+				}
+				if err != nil {
+					return
+				}
+			case bag.ID.Equal(oidCertBag):
+				var c *x509.Certificate
+				if c, err = parseCertBag(value); err != nil {
+					return
+				}
+				if c != nil {
+					certs = append(certs, c)
+				}
+			}
+		}
+	}
+	if prv == nil {
+		err = errors.New("Private key not found")
+		return
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return
+	}
+	for _, c := range certs {
+		if cert == nil && c.PublicKey != nil && pub.Equal(c.PublicKey) {
+			cert = c
+			continue
+		}
+		caCerts = append(caCerts, c)
+	}
+	return
+}
@@ -0,0 +1,170 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pkcs12
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+	"github.com/martinlindhe/gogost/x509"
+)
+
+const testIterations = 10
+
+// Fresh key with self-signed certificate.
+func newTestKey(t *testing.T, algo oid.Algorithm) (*gost3410.PrivateKey, *x509.Certificate) {
+	params, mode := gost3410.CurveParamsGostR34102012TC26ParamSetA256, gost3410.Mode2001
+	switch algo {
+	case oid.GostR34102001:
+		params = gost3410.CurveParamsGostR34102001CryptoProA
+	case oid.GostR34102012512:
+		params, mode = gost3410.CurveParamsGostR34102012TC26ParamSetC, gost3410.Mode2012
+	}
+	c, _ := gost3410.NewCurveFromParams(params)
+	prv, err := gost3410.GenPrivateKey(c, mode, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	pub, _ := prv.PublicKey()
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(1),
+		Subject:            pkix.Name{CommonName: algo.String()},
+		NotBefore:          time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:           time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC),
+		PublicKeyAlgorithm: algo,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, prv)
+	if err != nil {
+		t.FailNow()
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.FailNow()
+	}
+	return prv, cert
+}
+
+func TestEncodeDecode(t *testing.T) {
+	algos := []oid.Algorithm{
+		oid.GostR34102001,
+		oid.GostR34102012256,
+		oid.GostR34102012512,
+	}
+	prvs := make([]*gost3410.PrivateKey, len(algos))
+	certs := make([]*x509.Certificate, len(algos))
+	for i, algo := range algos {
+		prvs[i], certs[i] = newTestKey(t, algo)
+	}
+	password := []byte("Пароль для PFX")
+	for _, algo := range []oid.Algorithm{
+		oid.KuznyechikCTRACPKM,
+		oid.MagmaCTRACPKM,
+		oid.Gost2814789,
+	} {
+		for i := range algos {
+			other := certs[(i+1)%len(certs)]
+			der, err := Encode(
+				rand.Reader, prvs[i], certs[i], []*x509.Certificate{other},
+				password, &EncodeOptions{Algorithm: algo, Iterations: testIterations},
+			)
+			if err != nil {
+				t.FailNow()
+			}
+			prv, keyAlgo, cert, caCerts, err := Decode(der, password)
+			if err != nil ||
+				!prv.Equal(prvs[i]) ||
+				keyAlgo != algos[i] ||
+				cert == nil || !cert.Equal(certs[i]) ||
+				len(caCerts) != 1 || !caCerts[0].Equal(other) {
+				t.FailNow()
+			}
+		}
+	}
+}
+
+func TestDecodeWrongPassword(t *testing.T) {
+	prv, cert := newTestKey(t, oid.GostR34102012256)
+	der, err := Encode(
+		rand.Reader, prv, cert, nil,
+		[]byte("password"), &EncodeOptions{Iterations: testIterations},
+	)
+	if err != nil {
+		t.FailNow()
+	}
+	if _, _, _, _, err = Decode(der, []byte("Password")); err == nil {
+		t.FailNow()
+	}
+	der[len(der)/2] ^= 0x01
+	if _, _, _, _, err = Decode(der, []byte("password")); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEncodeMismatchedCertificate(t *testing.T) {
+	prv, _ := newTestKey(t, oid.GostR34102012256)
+	_, cert := newTestKey(t, oid.GostR34102012256)
+	if _, err := Encode(
+		rand.Reader, prv, cert, nil,
+		[]byte("password"), &EncodeOptions{Iterations: testIterations},
+	); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEncodeIterations(t *testing.T) {
+	prv, cert := newTestKey(t, oid.GostR34102012256)
+	for _, iterations := range []int{-1, x509.PBKDF2MaxIterations + 1} {
+		if _, err := Encode(
+			rand.Reader, prv, cert, nil,
+			[]byte("password"), &EncodeOptions{Iterations: iterations},
+		); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestDecodeMACIterations(t *testing.T) {
+	prv, cert := newTestKey(t, oid.GostR34102012256)
+	password := []byte("password")
+	der, err := Encode(
+		rand.Reader, prv, cert, nil,
+		password, &EncodeOptions{Iterations: testIterations},
+	)
+	if err != nil {
+		t.FailNow()
+	}
+	var pfx pfxPdu
+	if _, err = asn1.Unmarshal(der, &pfx); err != nil {
+		t.FailNow()
+	}
+	for _, iterations := range []int{0, -1, x509.PBKDF2MaxIterations + 1} {
+		pfx.MacData.Iterations = iterations
+		if der, err = asn1.Marshal(pfx); err != nil {
+			t.FailNow()
+		}
+		if _, _, _, _, err = Decode(der, password); err == nil {
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pkcs12
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+// PFX below is DER encoded by hand. Its MAC is computed with GNU Nettle
+// 3.8 PBKDF2 and HMAC over its own Streebog-512 implementation, as
+// R 1323565.1.041-2022 requires: the last 32 bytes of 96-byte PBKDF2
+// output are the key, 100 iterations keep the test fast. It holds
+// unencrypted key bag with 34.10-2012 256-bit key on CryptoPro-A curve,
+// certificate bag with its certificate and another one with the
+// issuer's certificate, both signed with Nettle's gostdsa_sign.
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var (
+	nettlePassword []byte = []byte("Пароль для PFX")
+	nettlePFX      []byte = hexDecode("" +
+		"308205d90201033082055b06092a864886f70d010701a082054c048205483082" +
+		"05443082054006092a864886f70d010701a08205310482052d3082052930818c" +
+		"060b2a864886f70d010c0a0101a04a3048020100301f06082a85030701010101" +
+		"301306072a85030202230106082a85030701010202042204208798a9b0c1d2e3" +
+		"f45feed67dccb89baa807162534435261788796a5b4c3d1e2f3131302f06092a" +
+		"864886f70d010915312204207a1ff96a3e7627934c1ff4b8c9040e50b7a8fba7" +
+		"73194a52149025f60da6485b30820249060b2a864886f70d010c0a0103a08202" +
+		"0530820201060a2a864886f70d01091601a08201f1048201ed308201e9308201" +
+		"55a00302010202024a1c300a06082a850307010103033039310b300906035504" +
+		"0613025255310f300d060355040a0c06476f474f53543119301706035504030c" +
+		"10476f474f5354204e6574746c65204341301e170d3230303130313030303030" +
+		"305a170d3339313233313233353935395a303b310b3009060355040613025255" +
+		"310f300d060355040a0c06476f474f5354311b301906035504030c12476f474f" +
+		"5354204e6574746c65204c6561663066301f06082a8503070101010130130607" +
+		"2a85030202230106082a8503070101020203430004406ee214d54d40ec2dd556" +
+		"22be9d26559aa9203792d9437e268e61512513e60f40508f5338c39099791fe6" +
+		"6e69510091073f0ad302bae77db7ed93e2e7acfd8d53a33e303c300e0603551d" +
+		"0f0101ff04040302078030130603551d0e040c040a1122334455667788990030" +
+		"150603551d23040e300c800a7f3a1c5e9b2d4f6a8c0e300a06082a8503070101" +
+		"03030381810054e4615761cd157fd21cc8d65ab82b5fbfc012c577612ceaeb51" +
+		"741ee19b8ba6c258f37771ad484efd610a725b622f61391d79c2ce54b8208b58" +
+		"6700070e562a75422c6debbccff398b575f5fc0bb4e5805fcc5515af6eae2a3a" +
+		"d96c13387b4a2c71b7abc9c7cbef2a3edc280844e3f7642b005cc699b03caa55" +
+		"2461b7635ce93131302f06092a864886f70d010915312204207a1ff96a3e7627" +
+		"934c1ff4b8c9040e50b7a8fba773194a52149025f60da6485b30820249060b2a" +
+		"864886f70d010c0a0103a082023830820234060a2a864886f70d01091601a082" +
+		"0224048202203082021c30820188a00302010202024a1b300a06082a85030701" +
+		"0103033039310b3009060355040613025255310f300d060355040a0c06476f47" +
+		"4f53543119301706035504030c10476f474f5354204e6574746c65204341301e" +
+		"170d3230303130313030303030305a170d3339313233313233353935395a3039" +
+		"310b3009060355040613025255310f300d060355040a0c06476f474f53543119" +
+		"301706035504030c10476f474f5354204e6574746c652043413081a030170608" +
+		"2a85030701010102300b06092a85030701020102010381840004818098ec54e2" +
+		"f3ff78ab7bead6364517585ce4a49f7e8a779780bb18370c01e0163a53de29ca" +
+		"076371450babf42f8d79d6371080d7a0e7ecfc592461c4156c1330fe0d9c1e7e" +
+		"5f980a5dc98be063122c02437441d762c890fa97175afd86190e784fa1fcc9d4" +
+		"40fe1e93ca376994bacb030e1fc1329d7b1570435f12e4fe4a18e879a3383036" +
+		"300f0603551d130101ff040530030101ff300e0603551d0f0101ff0404030201" +
+		"0630130603551d0e040c040a7f3a1c5e9b2d4f6a8c0e300a06082a8503070101" +
+		"0303038181007784909ab4b057807c01528628584b7f3c5472150931a04742df" +
+		"d0a65f0e82326e961b14f2a11d6c6a5ceda3ea72d74a7a8c5b0e2986e3d67920" +
+		"714f36fc053b5b76286c139fcca32955da8231b411a0ee3ae11bfa86d77b017e" +
+		"8eed0567321da7c2da4a4cde8acc94161b293bc0e436efa75242fdb1e8a8555c" +
+		"d92ae93b2d043075304e300a06082a850307010102030440a02966f654075ec0" +
+		"4a9dee729b0b453f0089e1ee25fbc6844e5294512191349fb42452789d4349b1" +
+		"f87df84b60152693a0ef2e862a758c8b0009fee3978763de0420404142434445" +
+		"464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f020164",
+	)
+	nettlePrv []byte = hexDecode("" +
+		"8798a9b0c1d2e3f45feed67dccb89baa807162534435261788796a5b4c3d1e2f",
+	)
+)
+
+// PFX below holds the same key and certificates, but the key is in
+// PKCS8ShroudedKeyBag and certificate bags are in EncryptedData. Both
+// are encrypted with PBES2: PBKDF2 over HMAC-Streebog-512 computed with
+// Nettle, 28147-89 in CFB mode with CryptoPro key meshing and
+// id-tc26-gost-28147-param-Z S-box computed with GnuTLS 3.7.9.
+// GnuTLS 3.7.9 gnutls_pkcs12_verify_mac and gnutls_pkcs12_simple_parse
+// accept it.
+var nettlePFXEncrypted []byte = hexDecode("" +
+	"308206fb0201033082067d06092a864886f70d010701a082066e0482066a3082" +
+	"06663082012206092a864886f70d010701a08201130482010f3082010b308201" +
+	"07060b2a864886f70d010c0a0102a081c43081c1307306092a864886f70d0105" +
+	"0d3066304306092a864886f70d01050c30360420606162636465666768696a6b" +
+	"6c6d6e6f707172737475767778797a7b7c7d7e7f020164020120300c06082a85" +
+	"0307010104020500301f06062a85030202153015040880818283848586870609" +
+	"2a8503070102050101044a9d49d5278543cac316d19249c6a7ffed6c66f94143" +
+	"7dcee1b5029f2a89efd07d282d9f30d55241132b8157e2a4e9e9192d867eaa13" +
+	"d0fd016ba888d94aadb46b19ff3692081497c06d5f3131302f06092a864886f7" +
+	"0d010915312204207a1ff96a3e7627934c1ff4b8c9040e50b7a8fba773194a52" +
+	"149025f60da6485b3082053c06092a864886f70d010706a082052d3082052902" +
+	"01003082052206092a864886f70d010701307306092a864886f70d01050d3066" +
+	"304306092a864886f70d01050c30360420909192939495969798999a9b9c9d9e" +
+	"9fa0a1a2a3a4a5a6a7a8a9aaabacadaeaf020164020120300c06082a85030701" +
+	"0104020500301f06062a850302021530150408b0b1b2b3b4b5b6b706092a8503" +
+	"0701020501018082049e789e5497a549db668620b604834d677fd0673dbfdadb" +
+	"a30b98e6c32566428f7623031f47224d530087bfbea3772eeecb221cfc401f4b" +
+	"232ecc25c8732c857018d03ef876ff6fa7a0209783086e2073bbc6ed38531d37" +
+	"33f452edb049458e3d60fa0cf6d7ed6a91beec84913844953e54911293c439f0" +
+	"b741ac086a1782ac99f9f10f16975aaa386438e4679a5081fd44bb1b4b89362c" +
+	"5da172619dd3b8a5c5638abfee7cf6119d8c94738ce4ce8015ff486735fa8434" +
+	"945cf33d7a12bf6b33c93fea7d15c9d0343f0321f0d74ff447190c4e5d5365e3" +
+	"9b8eb1274ebd475f0c93686ff8c8da608d944ca8111d62ba2ff433a3055a164a" +
+	"35928f91b90110ae2f33423072a8de2962ce727699fb4f415ce6559243da60c9" +
+	"8a8988e977303211b18c96712e843f602e8a67a1e7d2e01c55090441abc15a8e" +
+	"c62c648e1e0db96bf420399c64ae54e927fb4a6fc550d9113506a2fbb4b6be6b" +
+	"187ad359f90c330a1169a32eec6b1dd63218f098724829a9a1d81e1a15517521" +
+	"85b514c3c2b8d024518fa917e6213e5c4eefc8159639b04ce10c60f15bb07ee6" +
+	"cc5ee2890823093f2eb86563f73663ab4211e63457a4b2db3b9250dd8e94de06" +
+	"95b009a2007726866a59901c178bb01b3e05335bd1017a5b87d8050af13022a6" +
+	"06e4f5cab2ae0de6edef5100ab9d16853b385fb7ee07410362787fa16438763f" +
+	"4e3d34e7826ebdf7c385d6e9939201e63cdec3fe96bccd58c2a3d292af596c59" +
+	"dc98da43ce138e4b066545b73d9c4a0305df7ee72f51bb0c026ddc00b73bce24" +
+	"32ad669205b0b7aea2076ebce933fcb3a4a864d7e4299a025cb60f23f1800eae" +
+	"42fe720d9c0ad50dabdcf557ccc6b4c58e04a4c757a45bea673a66651645393c" +
+	"0a00049592a21a065cf0f643d6243491ed85ee6d2b6bb360c953c3b5d6b6753f" +
+	"fd214a39a0c8de38a8e175f5901335702440a975ed616c00427154b20101deb5" +
+	"fac89871ddc66ed1f9c922aeae1a635b94103456274863d8f758e047ba84699c" +
+	"66c4097ce3c7aa7f2af570a9afc877bdf622c4e6b4293baf136235aadd8ea1cc" +
+	"f0bfd5cb085d6736cea1da6cc7edc55bca01d49ab50a10827dcc8f9bd3044437" +
+	"072ad707f1ce1f06964957d344a607a80caf457afb901fe54d052818b89f7483" +
+	"9f41a19082a7e8db147d298d111f3f2a48fd90a91f18f9a2f285edee3bff3f8c" +
+	"6b72a39d129015569e8a032aa3d84759f5bb1c0692fd5b5230ae91f6be9999d5" +
+	"6cf543e051476ffdcd4d82add5e7ab3dbe3db23936e92d1cfd373eca52e0847f" +
+	"c3243cb48f6cd60c7de6c7697b6f86c4ca933e8d6f599951b970596f8a3da4a2" +
+	"f38ff66e430b82ea6614c4b51d643ac847d17488117b0a36cdc804c8009afa49" +
+	"d3633ed868b5ce89677c48e2eebae5ff6e737c5922374422b6eceb9ebd096503" +
+	"8004993ec97f6c2bf9b8eeb9680588f96a583434577182c88b8c29850573cf4b" +
+	"171418190d93c0b19d9e860c4dc1741031618c19944d658eaf6630ba9a7e9002" +
+	"ce34c031da83e1e1b6f78b45fcb65154a23b58542822e1eec3dc3b85dab16f70" +
+	"6beeb9250e2af3d40f4c1c906beebc96f1902f5175baf1fde5d3da2aa97c7b64" +
+	"db6649638e0f6dfc071f472ddc750ecbfc8d3bc26c85491c105c34303334dcbc" +
+	"e9f7a10dd06741913075304e300a06082a850307010102030440d86c9b80eba7" +
+	"f8b880715c1435f3370ae73453a48a9049627e6beb3647ad50ea0cb629981fa4" +
+	"9152cd2b0e6a6dd9325e0b36cde7e093812b7f5720f3071b9528042040414243" +
+	"4445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f020164",
+)
+
+func testNettlePFX(t *testing.T, der []byte) {
+	prv, algo, cert, caCerts, err := Decode(der, nettlePassword)
+	if err != nil ||
+		bytes.Compare(prv.Raw(), nettlePrv) != 0 ||
+		algo != oid.GostR34102012256 ||
+		cert == nil ||
+		cert.Subject.CommonName != "GoGOST Nettle Leaf" ||
+		len(caCerts) != 1 ||
+		caCerts[0].Subject.CommonName != "GoGOST Nettle CA" ||
+		cert.CheckSignatureFrom(caCerts[0]) != nil {
+		t.FailNow()
+	}
+	if _, _, _, _, err = Decode(der, []byte("Пароль для PFX ")); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestNettlePFX(t *testing.T) {
+	testNettlePFX(t, nettlePFX)
+}
+
+func TestNettlePFXEncrypted(t *testing.T) {
+	testNettlePFX(t, nettlePFXEncrypted)
+}
@@ -0,0 +1,300 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+type KeyUsage int
+
+const (
+	KeyUsageDigitalSignature KeyUsage = 1 << iota
+	KeyUsageContentCommitment
+	KeyUsageKeyEncipherment
+	KeyUsageDataEncipherment
+	KeyUsageKeyAgreement
+	KeyUsageCertSign
+	KeyUsageCRLSign
+	KeyUsageEncipherOnly
+	KeyUsageDecipherOnly
+)
+
+var (
+	oidExtensionSubjectKeyId     asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 29, 14}
+	oidExtensionKeyUsage         asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionBasicConstraints asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionAuthorityKeyId   asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 29, 35}
+	oidExtensionExtendedKeyUsage asn1.ObjectIdentifier = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+type certificate struct {
+	TBSCertificate     tbsCertificate
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type tbsCertificate struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          subjectPublicKeyInfo
+	UniqueId           asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueId    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"optional,explicit,tag:3"`
+}
+
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+type authKeyId struct {
+	Id []byte `asn1:"optional,tag:0"`
+}
+
+// X.509 certificate with GOST R 34.10 public key. PublicKey is nil and
+// PublicKeyAlgorithm is oid.Unknown for non-GOST keys.
+type Certificate struct {
+	Raw                     []byte
+	RawTBSCertificate       []byte
+	RawSubjectPublicKeyInfo []byte
+	RawSubject              []byte
+	RawIssuer               []byte
+
+	Signature          []byte
+	SignatureAlgorithm oid.Algorithm
+
+	PublicKeyAlgorithm oid.Algorithm
+	PublicKey          *gost3410.PublicKey
+
+	Version             int
+	SerialNumber        *big.Int
+	Issuer              pkix.Name
+	Subject             pkix.Name
+	NotBefore, NotAfter time.Time
+	KeyUsage            KeyUsage
+	ExtKeyUsage         []asn1.ObjectIdentifier
+
+	Extensions                  []pkix.Extension
+	ExtraExtensions             []pkix.Extension // used by CreateCertificate
+	UnhandledCriticalExtensions []asn1.ObjectIdentifier
+
+	BasicConstraintsValid bool
+	IsCA                  bool
+	MaxPathLen            int // -1 if unlimited
+
+	SubjectKeyId   []byte
+	AuthorityKeyId []byte
+}
+
+// Public key algorithm for the signature algorithm.
+func signatureKeyAlgorithm(algo oid.Algorithm) (oid.Algorithm, error) {
+	switch algo {
+	case oid.GostR341194WithGostR34102001:
+		return oid.GostR34102001, nil
+	case oid.GostR34102012256WithGostR34112012256:
+		return oid.GostR34102012256, nil
+	case oid.GostR34102012512WithGostR34112012512:
+		return oid.GostR34102012512, nil
+	}
+	return oid.Unknown, errors.New("Unsupported signature algorithm")
+}
+
+// Verify signature made with the algorithm over the signed data. Key
+// of one algorithm family (34.10-2001, 34.10-2012 256/512) must not be
+// used with the signature algorithm of another one. Signature is the
+// concatenation of big-endian s and r, and digest is treated as
+// little-endian number (RFC 4491).
+func checkSignature(
+	pub *gost3410.PublicKey,
+	pubAlgo, algo oid.Algorithm,
+	signed, signature []byte,
+) error {
+	keyAlgo, err := signatureKeyAlgorithm(algo)
+	if err != nil {
+		return err
+	}
+	if pub == nil {
+		return errors.New("Unsupported public key algorithm")
+	}
+	mode, err := algorithmMode(keyAlgo)
+	if err != nil {
+		return err
+	}
+	if pubAlgo != keyAlgo || pub.Mode() != mode {
+		return errors.New("Signature algorithm does not match public key")
+	}
+	valid, err := pub.VerifyMessageHash(algo.NewHash(), signed, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("Invalid signature")
+	}
+	return nil
+}
+
+// Parse GOST R 34.10 public key. Other keys are skipped and nil is
+// returned.
+func parseKeyInfo(spki *subjectPublicKeyInfo) (*gost3410.PublicKey, oid.Algorithm, error) {
+	if _, err := algorithmMode(oid.AlgorithmByOID(spki.Algorithm.Algorithm)); err != nil {
+		return nil, oid.Unknown, nil
+	}
+	return parsePublicKey(spki.Algorithm, spki.PublicKey.RightAlign())
+}
+
+func parseName(raw []byte) (pkix.Name, error) {
+	var name pkix.Name
+	var rdns pkix.RDNSequence
+	rest, err := asn1.Unmarshal(raw, &rdns)
+	if err != nil {
+		return name, err
+	}
+	if len(rest) > 0 {
+		return name, errors.New("Trailing data after name")
+	}
+	name.FillFromRDNSequence(&rdns)
+	return name, nil
+}
+
+func (c *Certificate) parseExtensions() error {
+	for _, ext := range c.Extensions {
+		var rest []byte
+		var err error
+		switch {
+		case ext.Id.Equal(oidExtensionSubjectKeyId):
+			rest, err = asn1.Unmarshal(ext.Value, &c.SubjectKeyId)
+		case ext.Id.Equal(oidExtensionKeyUsage):
+			var bits asn1.BitString
+			rest, err = asn1.Unmarshal(ext.Value, &bits)
+			for i := 0; i < 9; i++ {
+				if bits.At(i) != 0 {
+					c.KeyUsage |= 1 << uint(i)
+				}
+			}
+		case ext.Id.Equal(oidExtensionBasicConstraints):
+			var bc basicConstraints
+			rest, err = asn1.Unmarshal(ext.Value, &bc)
+			c.BasicConstraintsValid = true
+			c.IsCA = bc.IsCA
+			c.MaxPathLen = bc.MaxPathLen
+		case ext.Id.Equal(oidExtensionAuthorityKeyId):
+			var aki authKeyId
+			rest, err = asn1.Unmarshal(ext.Value, &aki)
+			c.AuthorityKeyId = aki.Id
+		case ext.Id.Equal(oidExtensionExtendedKeyUsage):
+			rest, err = asn1.Unmarshal(ext.Value, &c.ExtKeyUsage)
+		default:
+			if ext.Critical {
+				c.UnhandledCriticalExtensions = append(
+					c.UnhandledCriticalExtensions, ext.Id,
+				)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if len(rest) > 0 {
+			return errors.New("Trailing data after extension")
+		}
+	}
+	return nil
+}
+
+// Parse DER encoded X.509 certificate. GOST R 34.10 public key is
+// validated.
+func ParseCertificate(der []byte) (*Certificate, error) {
+	var cert certificate
+	rest, err := asn1.Unmarshal(der, &cert)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after certificate")
+	}
+	tbs := &cert.TBSCertificate
+	if !tbs.SignatureAlgorithm.Algorithm.Equal(cert.SignatureAlgorithm.Algorithm) {
+		return nil, errors.New("Signature algorithm mismatch")
+	}
+	c := Certificate{
+		Raw:                     der,
+		RawTBSCertificate:       tbs.Raw,
+		RawSubjectPublicKeyInfo: tbs.PublicKey.Raw,
+		RawSubject:              tbs.Subject.FullBytes,
+		RawIssuer:               tbs.Issuer.FullBytes,
+		Signature:               cert.SignatureValue.RightAlign(),
+		SignatureAlgorithm:      oid.AlgorithmByOID(cert.SignatureAlgorithm.Algorithm),
+		Version:                 tbs.Version + 1,
+		SerialNumber:            tbs.SerialNumber,
+		NotBefore:               tbs.Validity.NotBefore,
+		NotAfter:                tbs.Validity.NotAfter,
+		Extensions:              tbs.Extensions,
+		MaxPathLen:              -1,
+	}
+	if c.PublicKey, c.PublicKeyAlgorithm, err = parseKeyInfo(&tbs.PublicKey); err != nil {
+		return nil, err
+	}
+	if c.Issuer, err = parseName(c.RawIssuer); err != nil {
+		return nil, err
+	}
+	if c.Subject, err = parseName(c.RawSubject); err != nil {
+		return nil, err
+	}
+	if err = c.parseExtensions(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Certificate) Equal(other *Certificate) bool {
+	return bytes.Equal(c.Raw, other.Raw)
+}
+
+// Verify signature made with the algorithm by the certificate's key.
+func (c *Certificate) CheckSignature(algo oid.Algorithm, signed, signature []byte) error {
+	return checkSignature(c.PublicKey, c.PublicKeyAlgorithm, algo, signed, signature)
+}
+
+// Verify that the certificate is signed by the parent, which must be a
+// certification authority.
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if parent.Version == 3 && !(parent.BasicConstraintsValid && parent.IsCA) {
+		return errors.New("Parent is not a certification authority")
+	}
+	if parent.KeyUsage != 0 && parent.KeyUsage&KeyUsageCertSign == 0 {
+		return errors.New("Parent is not allowed to sign certificates")
+	}
+	return parent.CheckSignature(c.SignatureAlgorithm, c.RawTBSCertificate, c.Signature)
+}
@@ -0,0 +1,241 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+var (
+	testNotBefore time.Time = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testNotAfter  time.Time = time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC)
+	testNow       time.Time = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testKey struct {
+	prv     *gost3410.PrivateKey
+	pub     *gost3410.PublicKey
+	algo    oid.Algorithm
+	sigAlgo oid.Algorithm
+}
+
+func newTestKey(t *testing.T, params *gost3410.CurveParams, mode gost3410.Mode, algo, sigAlgo oid.Algorithm) *testKey {
+	c, err := gost3410.NewCurveFromParams(*params)
+	if err != nil {
+		t.FailNow()
+	}
+	prv, err := gost3410.GenPrivateKey(c, mode, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	pub, _ := prv.PublicKey()
+	return &testKey{prv, pub, algo, sigAlgo}
+}
+
+func newTestKey2001(t *testing.T) *testKey {
+	return newTestKey(
+		t, &gost3410.CurveParamsGostR34102001CryptoProA, gost3410.Mode2001,
+		oid.GostR34102001, oid.GostR341194WithGostR34102001,
+	)
+}
+
+func newTestKey2012256(t *testing.T) *testKey {
+	return newTestKey(
+		t, &gost3410.CurveParamsGostR34102012TC26ParamSetA256, gost3410.Mode2001,
+		oid.GostR34102012256, oid.GostR34102012256WithGostR34112012256,
+	)
+}
+
+func newTestKey2012512(t *testing.T) *testKey {
+	return newTestKey(
+		t, &gost3410.CurveParamsGostR34102012TC26ParamSetA, gost3410.Mode2012,
+		oid.GostR34102012512, oid.GostR34102012512WithGostR34112012512,
+	)
+}
+
+func testName(t *testing.T, cn string) asn1.RawValue {
+	raw, err := asn1.Marshal(pkix.Name{CommonName: cn}.ToRDNSequence())
+	if err != nil {
+		t.FailNow()
+	}
+	return asn1.RawValue{FullBytes: raw}
+}
+
+func testExtension(t *testing.T, id asn1.ObjectIdentifier, critical bool, v interface{}) pkix.Extension {
+	raw, err := asn1.Marshal(v)
+	if err != nil {
+		t.FailNow()
+	}
+	return pkix.Extension{Id: id, Critical: critical, Value: raw}
+}
+
+func testCAExtensions(t *testing.T, maxPathLen int) []pkix.Extension {
+	return []pkix.Extension{
+		testExtension(t, oidExtensionBasicConstraints, true, basicConstraints{true, maxPathLen}),
+		testExtension(t, oidExtensionKeyUsage, true, asn1.BitString{
+			Bytes: []byte{0x06}, BitLength: 7,
+		}),
+	}
+}
+
+func signTest(t *testing.T, signer *testKey, tbs []byte) asn1.BitString {
+	signature, err := signer.prv.SignMessageHash(signer.sigAlgo.NewHash(), tbs, rand.Reader)
+	if err != nil {
+		t.FailNow()
+	}
+	return asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)}
+}
+
+func makeTestCertificate(t *testing.T, serial int64, subject, issuer string, key, signer *testKey, exts []pkix.Extension) *Certificate {
+	spkiRaw, err := MarshalPKIXPublicKey(key.pub, key.algo)
+	if err != nil {
+		t.FailNow()
+	}
+	var spki subjectPublicKeyInfo
+	if _, err = asn1.Unmarshal(spkiRaw, &spki); err != nil {
+		t.FailNow()
+	}
+	sigAlgo := pkix.AlgorithmIdentifier{Algorithm: signer.sigAlgo.OID()}
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       big.NewInt(serial),
+		SignatureAlgorithm: sigAlgo,
+		Issuer:             testName(t, issuer),
+		Validity:           validity{testNotBefore, testNotAfter},
+		Subject:            testName(t, subject),
+		PublicKey:          spki,
+		Extensions:         exts,
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	der, err := asn1.Marshal(certificate{
+		TBSCertificate:     tbsCertificate{Raw: tbs},
+		SignatureAlgorithm: sigAlgo,
+		SignatureValue:     signTest(t, signer, tbs),
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	cert, err := ParseCertificate(der)
+	if err != nil {
+		t.FailNow()
+	}
+	return cert
+}
+
+func TestCertificateParse(t *testing.T) {
+	ca := newTestKey2012512(t)
+	key := newTestKey2001(t)
+	exts := append(
+		testCAExtensions(t, 3),
+		testExtension(t, oidExtensionSubjectKeyId, false, []byte{1, 2, 3}),
+		testExtension(t, oidExtensionAuthorityKeyId, false, authKeyId{[]byte{4, 5}}),
+	)
+	cert := makeTestCertificate(t, 12345, "Test", "CA", key, ca, exts)
+	if cert.Version != 3 ||
+		cert.SerialNumber.Int64() != 12345 ||
+		cert.Subject.CommonName != "Test" ||
+		cert.Issuer.CommonName != "CA" ||
+		!cert.NotBefore.Equal(testNotBefore) ||
+		!cert.NotAfter.Equal(testNotAfter) ||
+		cert.SignatureAlgorithm != oid.GostR34102012512WithGostR34112012512 ||
+		cert.PublicKeyAlgorithm != oid.GostR34102001 ||
+		!cert.PublicKey.Equal(key.pub) ||
+		!cert.BasicConstraintsValid ||
+		!cert.IsCA ||
+		cert.MaxPathLen != 3 ||
+		cert.KeyUsage != KeyUsageCertSign|KeyUsageCRLSign ||
+		len(cert.UnhandledCriticalExtensions) != 0 ||
+		bytes.Compare(cert.SubjectKeyId, []byte{1, 2, 3}) != 0 ||
+		bytes.Compare(cert.AuthorityKeyId, []byte{4, 5}) != 0 {
+		t.FailNow()
+	}
+	pub, _, err := ParsePKIXPublicKey(cert.RawSubjectPublicKeyInfo)
+	if err != nil || !pub.Equal(key.pub) {
+		t.FailNow()
+	}
+	cert = makeTestCertificate(t, 1, "Test", "CA", key, ca, nil)
+	if cert.BasicConstraintsValid || cert.IsCA || cert.MaxPathLen != -1 || cert.KeyUsage != 0 {
+		t.FailNow()
+	}
+}
+
+func TestCertificateSignatureAlgorithms(t *testing.T) {
+	for _, key := range []*testKey{
+		newTestKey2001(t),
+		newTestKey2012256(t),
+		newTestKey2012512(t),
+	} {
+		cert := makeTestCertificate(t, 1, "CA", "CA", key, key, testCAExtensions(t, -1))
+		if cert.CheckSignatureFrom(cert) != nil {
+			t.FailNow()
+		}
+		cert.Signature[0] ^= 0x01
+		if cert.CheckSignatureFrom(cert) == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestCertificateSignatureModeMismatch(t *testing.T) {
+	ca := newTestKey2012512(t)
+	cert := makeTestCertificate(t, 1, "CA", "CA", ca, ca, testCAExtensions(t, -1))
+	key := newTestKey2001(t)
+	other := makeTestCertificate(t, 1, "CA", "CA", key, key, testCAExtensions(t, -1))
+	if cert.CheckSignatureFrom(other) == nil {
+		t.FailNow()
+	}
+}
+
+func TestCertificateSignatureFamilyMismatch(t *testing.T) {
+	for _, key := range []*testKey{newTestKey2001(t), newTestKey2012256(t)} {
+		signer := *key
+		if key.algo == oid.GostR34102001 {
+			signer.sigAlgo = oid.GostR34102012256WithGostR34112012256
+		} else {
+			signer.sigAlgo = oid.GostR341194WithGostR34102001
+		}
+		cert := makeTestCertificate(t, 1, "CA", "CA", &signer, &signer, testCAExtensions(t, -1))
+		if cert.CheckSignatureFrom(cert) == nil {
+			t.FailNow()
+		}
+		signer.sigAlgo = key.sigAlgo
+		cert = makeTestCertificate(t, 1, "CA", "CA", &signer, &signer, testCAExtensions(t, -1))
+		if cert.CheckSignatureFrom(cert) != nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestCertificateNotCA(t *testing.T) {
+	key := newTestKey2012256(t)
+	cert := makeTestCertificate(t, 1, "CA", "CA", key, key, nil)
+	if cert.CheckSignatureFrom(cert) == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,248 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+// Default public key algorithm for the key mode: 34.10-2012 ones.
+func defaultPublicKeyAlgorithm(mode gost3410.Mode) oid.Algorithm {
+	if mode == gost3410.Mode2012 {
+		return oid.GostR34102012512
+	}
+	return oid.GostR34102012256
+}
+
+// Default signature algorithm for the public key algorithm: 34.10-2001
+// with 34.11-94 or 34.10-2012 with Streebog of corresponding size.
+func defaultSignatureAlgorithm(keyAlgo oid.Algorithm) oid.Algorithm {
+	switch keyAlgo {
+	case oid.GostR34102001:
+		return oid.GostR341194WithGostR34102001
+	case oid.GostR34102012512:
+		return oid.GostR34102012512WithGostR34112012512
+	}
+	return oid.GostR34102012256WithGostR34112012256
+}
+
+func marshalPublicKeyInfo(pub *gost3410.PublicKey, algo oid.Algorithm) (subjectPublicKeyInfo, error) {
+	if algo == oid.Unknown {
+		algo = defaultPublicKeyAlgorithm(pub.Mode())
+	}
+	raw, err := MarshalPKIXPublicKey(pub, algo)
+	return subjectPublicKeyInfo{Raw: raw}, err
+}
+
+// Signature algorithm for the signer's key of keyAlgo public key
+// algorithm. Unknown ones are replaced with defaults.
+func signatureAlgorithm(prv *gost3410.PrivateKey, keyAlgo, algo oid.Algorithm) (oid.Algorithm, error) {
+	if keyAlgo == oid.Unknown {
+		keyAlgo = defaultPublicKeyAlgorithm(prv.Mode())
+	}
+	if algo == oid.Unknown {
+		algo = defaultSignatureAlgorithm(keyAlgo)
+	}
+	sigKeyAlgo, err := signatureKeyAlgorithm(algo)
+	if err != nil {
+		return algo, err
+	}
+	mode, err := algorithmMode(keyAlgo)
+	if err != nil {
+		return algo, err
+	}
+	if sigKeyAlgo != keyAlgo || mode != prv.Mode() {
+		return algo, errors.New("Signature algorithm does not match private key")
+	}
+	return algo, nil
+}
+
+// Sign the data, producing signature in RFC 4491 format.
+func sign(rand io.Reader, prv *gost3410.PrivateKey, algo oid.Algorithm, signed []byte) (asn1.BitString, error) {
+	signature, err := prv.SignMessageHash(algo.NewHash(), signed, rand)
+	if err != nil {
+		return asn1.BitString{}, err
+	}
+	return asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)}, nil
+}
+
+func marshalName(raw []byte, name pkix.Name) (asn1.RawValue, error) {
+	if len(raw) == 0 {
+		var err error
+		raw, err = asn1.Marshal(name.ToRDNSequence())
+		if err != nil {
+			return asn1.RawValue{}, err
+		}
+	}
+	return asn1.RawValue{FullBytes: raw}, nil
+}
+
+func keyUsageBits(ku KeyUsage) asn1.BitString {
+	var b [2]byte
+	bitLength := 0
+	for i := 0; i < 9; i++ {
+		if ku&(1<<uint(i)) != 0 {
+			b[i/8] |= 0x80 >> uint(i%8)
+			bitLength = i + 1
+		}
+	}
+	return asn1.BitString{Bytes: b[:(bitLength+7)/8], BitLength: bitLength}
+}
+
+func hasExtension(exts []pkix.Extension, id asn1.ObjectIdentifier) bool {
+	for _, ext := range exts {
+		if ext.Id.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (template *Certificate) buildExtensions(authorityKeyId []byte) ([]pkix.Extension, error) {
+	var exts []pkix.Extension
+	add := func(id asn1.ObjectIdentifier, critical bool, v interface{}) error {
+		if hasExtension(template.ExtraExtensions, id) {
+			return nil
+		}
+		value, err := asn1.Marshal(v)
+		if err != nil {
+			return err
+		}
+		exts = append(exts, pkix.Extension{Id: id, Critical: critical, Value: value})
+		return nil
+	}
+	if len(template.SubjectKeyId) > 0 {
+		if err := add(oidExtensionSubjectKeyId, false, template.SubjectKeyId); err != nil {
+			return nil, err
+		}
+	}
+	if template.KeyUsage != 0 {
+		if err := add(oidExtensionKeyUsage, true, keyUsageBits(template.KeyUsage)); err != nil {
+			return nil, err
+		}
+	}
+	if len(template.ExtKeyUsage) > 0 {
+		if err := add(oidExtensionExtendedKeyUsage, false, template.ExtKeyUsage); err != nil {
+			return nil, err
+		}
+	}
+	if template.BasicConstraintsValid {
+		bc := basicConstraints{IsCA: template.IsCA, MaxPathLen: -1}
+		if template.IsCA {
+			bc.MaxPathLen = template.MaxPathLen
+		}
+		if err := add(oidExtensionBasicConstraints, true, bc); err != nil {
+			return nil, err
+		}
+	}
+	if len(authorityKeyId) > 0 {
+		if err := add(oidExtensionAuthorityKeyId, false, authKeyId{authorityKeyId}); err != nil {
+			return nil, err
+		}
+	}
+	return append(exts, template.ExtraExtensions...), nil
+}
+
+// Create DER encoded X.509 v3 certificate for the public key, signed
+// by the parent's private key. Template is self-signed if parent is
+// the template itself. Following template fields are used:
+// SerialNumber, Subject, NotBefore, NotAfter, KeyUsage, ExtKeyUsage,
+// BasicConstraintsValid, IsCA, MaxPathLen (-1 for unlimited path
+// length, 0 is pathLenConstraint of zero), SubjectKeyId,
+// ExtraExtensions, PublicKeyAlgorithm and SignatureAlgorithm. Unknown
+// public key algorithm is replaced with 34.10-2012 one, unknown
+// signature algorithm with the one matching parent's public key
+// algorithm. Parent's SubjectKeyId is used as AuthorityKeyId for non
+// self-signed certificates.
+func CreateCertificate(
+	rand io.Reader,
+	template, parent *Certificate,
+	pub *gost3410.PublicKey,
+	prv *gost3410.PrivateKey,
+) ([]byte, error) {
+	if template.SerialNumber == nil || template.SerialNumber.Sign() < 0 {
+		return nil, errors.New("Invalid serial number")
+	}
+	if parent.PublicKey != nil {
+		signerPub, err := prv.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if !signerPub.Equal(parent.PublicKey) {
+			return nil, errors.New("Private key does not match parent's public key")
+		}
+	}
+	sigAlgo, err := signatureAlgorithm(prv, parent.PublicKeyAlgorithm, template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	spki, err := marshalPublicKeyInfo(pub, template.PublicKeyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := marshalName(parent.RawSubject, parent.Subject)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := marshalName(template.RawSubject, template.Subject)
+	if err != nil {
+		return nil, err
+	}
+	var authorityKeyId []byte
+	if !bytes.Equal(issuer.FullBytes, subject.FullBytes) {
+		authorityKeyId = parent.SubjectKeyId
+	}
+	exts, err := template.buildExtensions(authorityKeyId)
+	if err != nil {
+		return nil, err
+	}
+	ai := pkix.AlgorithmIdentifier{Algorithm: sigAlgo.OID()}
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       template.SerialNumber,
+		SignatureAlgorithm: ai,
+		Issuer:             issuer,
+		Validity: validity{
+			template.NotBefore.UTC().Truncate(time.Second),
+			template.NotAfter.UTC().Truncate(time.Second),
+		},
+		Subject:    subject,
+		PublicKey:  spki,
+		Extensions: exts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signature, err := sign(rand, prv, sigAlgo, tbs)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(certificate{
+		TBSCertificate:     tbsCertificate{Raw: tbs},
+		SignatureAlgorithm: ai,
+		SignatureValue:     signature,
+	})
+}
@@ -0,0 +1,123 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+func TestCreateCertificate(t *testing.T) {
+	caKey := newTestKey2012512(t)
+	caTemplate := &Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "CA", Country: []string{"RU"}},
+		NotBefore:             testNotBefore,
+		NotAfter:              testNotAfter,
+		KeyUsage:              KeyUsageCertSign | KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            -1,
+		SubjectKeyId:          []byte{1, 2, 3},
+	}
+	der, err := CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.pub, caKey.prv)
+	if err != nil {
+		t.FailNow()
+	}
+	ca, err := ParseCertificate(der)
+	if err != nil ||
+		ca.Subject.CommonName != "CA" ||
+		ca.Subject.Country[0] != "RU" ||
+		ca.Issuer.CommonName != "CA" ||
+		ca.SignatureAlgorithm != oid.GostR34102012512WithGostR34112012512 ||
+		ca.PublicKeyAlgorithm != oid.GostR34102012512 ||
+		!ca.IsCA ||
+		ca.MaxPathLen != -1 ||
+		ca.KeyUsage != caTemplate.KeyUsage ||
+		bytes.Compare(ca.SubjectKeyId, caTemplate.SubjectKeyId) != 0 ||
+		len(ca.AuthorityKeyId) != 0 ||
+		ca.CheckSignatureFrom(ca) != nil {
+		t.FailNow()
+	}
+
+	key := newTestKey2001(t)
+	extKeyUsage := []asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 2}}
+	template := &Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Leaf"},
+		NotBefore:    testNotBefore.Add(time.Hour),
+		NotAfter:     testNotAfter.Add(-time.Hour),
+		KeyUsage:     KeyUsageDigitalSignature | KeyUsageKeyAgreement | KeyUsageDecipherOnly,
+		ExtKeyUsage:  extKeyUsage,
+	}
+	der, err = CreateCertificate(rand.Reader, template, ca, key.pub, caKey.prv)
+	if err != nil {
+		t.FailNow()
+	}
+	leaf, err := ParseCertificate(der)
+	if err != nil ||
+		leaf.Version != 3 ||
+		leaf.SerialNumber.Int64() != 2 ||
+		leaf.Issuer.CommonName != "CA" ||
+		bytes.Compare(leaf.RawIssuer, ca.RawSubject) != 0 ||
+		!leaf.NotBefore.Equal(template.NotBefore) ||
+		!leaf.NotAfter.Equal(template.NotAfter) ||
+		leaf.PublicKeyAlgorithm != oid.GostR34102012256 ||
+		!leaf.PublicKey.Equal(key.pub) ||
+		leaf.BasicConstraintsValid ||
+		leaf.KeyUsage != template.KeyUsage ||
+		len(leaf.ExtKeyUsage) != 1 ||
+		!leaf.ExtKeyUsage[0].Equal(extKeyUsage[0]) ||
+		bytes.Compare(leaf.AuthorityKeyId, ca.SubjectKeyId) != 0 ||
+		len(leaf.SubjectKeyId) != 0 {
+		t.FailNow()
+	}
+	chain, err := leaf.Verify(VerifyOptions{
+		Roots:       []*Certificate{ca},
+		CurrentTime: testNow,
+	})
+	if err != nil || len(chain) != 2 {
+		t.FailNow()
+	}
+}
+
+func TestCreateCertificateWrongKey(t *testing.T) {
+	caKey := newTestKey2012512(t)
+	ca := makeTestCertificate(t, 1, "CA", "CA", caKey, caKey, testCAExtensions(t, -1))
+	key := newTestKey2012512(t)
+	template := &Certificate{SerialNumber: big.NewInt(2)}
+	if _, err := CreateCertificate(rand.Reader, template, ca, key.pub, key.prv); err == nil {
+		t.FailNow()
+	}
+	template.SignatureAlgorithm = oid.GostR34102012256WithGostR34112012256
+	if _, err := CreateCertificate(rand.Reader, template, template, key.pub, key.prv); err == nil {
+		t.FailNow()
+	}
+	template.SignatureAlgorithm = oid.Unknown
+	template.SerialNumber = nil
+	if _, err := CreateCertificate(rand.Reader, template, template, key.pub, key.prv); err == nil {
+		t.FailNow()
+	}
+}
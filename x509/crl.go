@@ -0,0 +1,147 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+type certificateList struct {
+	TBSCertList        tbsCertList
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type tbsCertList struct {
+	Raw                 asn1.RawContent
+	Version             int `asn1:"optional,default:0"`
+	Signature           pkix.AlgorithmIdentifier
+	Issuer              asn1.RawValue
+	ThisUpdate          time.Time
+	NextUpdate          time.Time            `asn1:"optional"`
+	RevokedCertificates []RevokedCertificate `asn1:"optional"`
+	Extensions          []pkix.Extension     `asn1:"optional,explicit,tag:0"`
+}
+
+type RevokedCertificate struct {
+	SerialNumber   *big.Int
+	RevocationTime time.Time
+	Extensions     []pkix.Extension `asn1:"optional"`
+}
+
+// X.509 certificate revocation list signed with GOST R 34.10.
+type RevocationList struct {
+	Raw                  []byte
+	RawTBSRevocationList []byte
+	RawIssuer            []byte
+
+	Signature          []byte
+	SignatureAlgorithm oid.Algorithm
+
+	Issuer              pkix.Name
+	ThisUpdate          time.Time
+	NextUpdate          time.Time // zero if absent
+	RevokedCertificates []RevokedCertificate
+	Extensions          []pkix.Extension
+
+	// Critical CRL and CRL entry extensions: none of them are handled
+	UnhandledCriticalExtensions []asn1.ObjectIdentifier
+}
+
+// Parse DER encoded X.509 CRL.
+func ParseRevocationList(der []byte) (*RevocationList, error) {
+	var cl certificateList
+	rest, err := asn1.Unmarshal(der, &cl)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after CRL")
+	}
+	tbs := &cl.TBSCertList
+	if !tbs.Signature.Algorithm.Equal(cl.SignatureAlgorithm.Algorithm) {
+		return nil, errors.New("Signature algorithm mismatch")
+	}
+	crl := RevocationList{
+		Raw:                  der,
+		RawTBSRevocationList: tbs.Raw,
+		RawIssuer:            tbs.Issuer.FullBytes,
+		Signature:            cl.SignatureValue.RightAlign(),
+		SignatureAlgorithm:   oid.AlgorithmByOID(cl.SignatureAlgorithm.Algorithm),
+		ThisUpdate:           tbs.ThisUpdate,
+		NextUpdate:           tbs.NextUpdate,
+		RevokedCertificates:  tbs.RevokedCertificates,
+		Extensions:           tbs.Extensions,
+	}
+	if crl.Issuer, err = parseName(crl.RawIssuer); err != nil {
+		return nil, err
+	}
+	crl.UnhandledCriticalExtensions = appendCritical(nil, crl.Extensions)
+	for _, revoked := range crl.RevokedCertificates {
+		crl.UnhandledCriticalExtensions = appendCritical(
+			crl.UnhandledCriticalExtensions, revoked.Extensions,
+		)
+	}
+	return &crl, nil
+}
+
+func appendCritical(ids []asn1.ObjectIdentifier, exts []pkix.Extension) []asn1.ObjectIdentifier {
+	for _, ext := range exts {
+		if ext.Critical {
+			ids = append(ids, ext.Id)
+		}
+	}
+	return ids
+}
+
+// Verify that the CRL is signed by the issuer's certificate.
+func (crl *RevocationList) CheckSignatureFrom(issuer *Certificate) error {
+	if issuer.KeyUsage != 0 && issuer.KeyUsage&KeyUsageCRLSign == 0 {
+		return errors.New("Issuer is not allowed to sign CRLs")
+	}
+	return issuer.CheckSignature(
+		crl.SignatureAlgorithm,
+		crl.RawTBSRevocationList,
+		crl.Signature,
+	)
+}
+
+// Is the CRL valid at the given time: issued not later than it and
+// NextUpdate, if present, has not passed.
+func (crl *RevocationList) IsCurrent(now time.Time) bool {
+	if now.Before(crl.ThisUpdate) {
+		return false
+	}
+	return crl.NextUpdate.IsZero() || !now.After(crl.NextUpdate)
+}
+
+// Is certificate with the given serial number listed in the CRL.
+func (crl *RevocationList) IsRevoked(serial *big.Int) bool {
+	for _, revoked := range crl.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(serial) == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+func makeTestRevocationList(t *testing.T, issuer string, signer *testKey, serials ...int64) *RevocationList {
+	return makeTestRevocationListExt(t, issuer, signer, nil, serials...)
+}
+
+func makeTestRevocationListExt(
+	t *testing.T,
+	issuer string,
+	signer *testKey,
+	exts []pkix.Extension,
+	serials ...int64,
+) *RevocationList {
+	var revoked []RevokedCertificate
+	for _, serial := range serials {
+		revoked = append(revoked, RevokedCertificate{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: testNotBefore,
+		})
+	}
+	sigAlgo := pkix.AlgorithmIdentifier{Algorithm: signer.sigAlgo.OID()}
+	tbs, err := asn1.Marshal(tbsCertList{
+		Version:             1,
+		Signature:           sigAlgo,
+		Issuer:              testName(t, issuer),
+		ThisUpdate:          testNotBefore,
+		NextUpdate:          testNotAfter,
+		RevokedCertificates: revoked,
+		Extensions:          exts,
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	der, err := asn1.Marshal(certificateList{
+		TBSCertList:        tbsCertList{Raw: tbs},
+		SignatureAlgorithm: sigAlgo,
+		SignatureValue:     signTest(t, signer, tbs),
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	crl, err := ParseRevocationList(der)
+	if err != nil {
+		t.FailNow()
+	}
+	return crl
+}
+
+func TestRevocationListParse(t *testing.T) {
+	tc := newTestChain(t, -1)
+	crl := makeTestRevocationList(t, "Inter", tc.interKey, 5, 3)
+	if crl.Issuer.CommonName != "Inter" ||
+		crl.SignatureAlgorithm != oid.GostR34102012256WithGostR34112012256 ||
+		!crl.ThisUpdate.Equal(testNotBefore) ||
+		!crl.NextUpdate.Equal(testNotAfter) ||
+		len(crl.RevokedCertificates) != 2 ||
+		!crl.IsRevoked(big.NewInt(3)) ||
+		crl.IsRevoked(big.NewInt(4)) {
+		t.FailNow()
+	}
+	if crl.CheckSignatureFrom(tc.inter) != nil {
+		t.FailNow()
+	}
+	if crl.CheckSignatureFrom(tc.root) == nil {
+		t.FailNow()
+	}
+	crl = makeTestRevocationList(t, "Inter", tc.interKey)
+	if len(crl.RevokedCertificates) != 0 || crl.IsRevoked(big.NewInt(3)) {
+		t.FailNow()
+	}
+}
+
+func TestVerifyRevoked(t *testing.T) {
+	tc := newTestChain(t, -1)
+	opts := VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CRLs: []*RevocationList{
+			makeTestRevocationList(t, "Inter", tc.interKey, 4),
+			// CRL of another issuer is ignored
+			makeTestRevocationList(t, "Root", tc.rootKey, 3),
+		},
+		CurrentTime: testNow,
+	}
+	if _, err := tc.leaf.Verify(opts); err != nil {
+		t.FailNow()
+	}
+	opts.CRLs = append(opts.CRLs, makeTestRevocationList(t, "Inter", tc.interKey, 3))
+	if _, err := tc.leaf.Verify(opts); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyForgedRevocationList(t *testing.T) {
+	tc := newTestChain(t, -1)
+	opts := VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CRLs:          []*RevocationList{makeTestRevocationList(t, "Inter", tc.rootKey)},
+		CurrentTime:   testNow,
+	}
+	if _, err := tc.leaf.Verify(opts); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyRevocationListCriticalExtension(t *testing.T) {
+	tc := newTestChain(t, -1)
+	// Issuing distribution point
+	ext := pkix.Extension{
+		Id:       asn1.ObjectIdentifier{2, 5, 29, 28},
+		Critical: true,
+		Value:    []byte{0x30, 0x00},
+	}
+	crl := makeTestRevocationListExt(t, "Inter", tc.interKey, []pkix.Extension{ext})
+	if len(crl.UnhandledCriticalExtensions) != 1 ||
+		!crl.UnhandledCriticalExtensions[0].Equal(ext.Id) {
+		t.FailNow()
+	}
+	opts := VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CRLs:          []*RevocationList{crl},
+		CurrentTime:   testNow,
+	}
+	if _, err := tc.leaf.Verify(opts); err == nil {
+		t.FailNow()
+	}
+	ext.Critical = false
+	crl = makeTestRevocationListExt(t, "Inter", tc.interKey, []pkix.Extension{ext})
+	opts.CRLs = []*RevocationList{crl}
+	if _, err := tc.leaf.Verify(opts); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyOutdatedRevocationList(t *testing.T) {
+	tc := newTestChain(t, -1)
+	crl := makeTestRevocationList(t, "Inter", tc.interKey, 4)
+	if !crl.IsCurrent(testNow) ||
+		crl.IsCurrent(testNotBefore.Add(-time.Second)) ||
+		crl.IsCurrent(testNotAfter.Add(time.Second)) {
+		t.FailNow()
+	}
+	opts := VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CRLs:          []*RevocationList{crl},
+		CurrentTime:   testNow,
+	}
+	if _, err := tc.leaf.Verify(opts); err != nil {
+		t.FailNow()
+	}
+	crl.NextUpdate = testNow.Add(-time.Second)
+	if _, err := tc.leaf.Verify(opts); err == nil {
+		t.FailNow()
+	}
+	crl.NextUpdate = time.Time{}
+	if _, err := tc.leaf.Verify(opts); err != nil {
+		t.FailNow()
+	}
+}
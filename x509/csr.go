@@ -0,0 +1,183 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+var oidExtensionRequest asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+
+type certificateRequest struct {
+	TBSCSR             tbsCertificateRequest
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type tbsCertificateRequest struct {
+	Raw           asn1.RawContent
+	Version       int
+	Subject       asn1.RawValue
+	PublicKey     subjectPublicKeyInfo
+	RawAttributes []asn1.RawValue `asn1:"tag:0"`
+}
+
+type csrAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// PKCS#10 certificate signing request (RFC 2986) with GOST R 34.10
+// public key. Extensions are taken from the extension request
+// attribute.
+type CertificateRequest struct {
+	Raw                      []byte
+	RawTBSCertificateRequest []byte
+	RawSubjectPublicKeyInfo  []byte
+	RawSubject               []byte
+
+	Version            int
+	Signature          []byte
+	SignatureAlgorithm oid.Algorithm
+
+	PublicKeyAlgorithm oid.Algorithm
+	PublicKey          *gost3410.PublicKey
+
+	Subject         pkix.Name
+	Extensions      []pkix.Extension
+	ExtraExtensions []pkix.Extension // used by CreateCertificateRequest
+}
+
+// Create DER encoded PKCS#10 certificate signing request for the
+// private key's public one. Following template fields are used:
+// Subject, ExtraExtensions, PublicKeyAlgorithm and SignatureAlgorithm.
+// Unknown public key algorithm is replaced with 34.10-2012 one, unknown
+// signature algorithm with the one matching it.
+func CreateCertificateRequest(
+	rand io.Reader,
+	template *CertificateRequest,
+	prv *gost3410.PrivateKey,
+) ([]byte, error) {
+	sigAlgo, err := signatureAlgorithm(prv, template.PublicKeyAlgorithm, template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	spki, err := marshalPublicKeyInfo(pub, template.PublicKeyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := marshalName(template.RawSubject, template.Subject)
+	if err != nil {
+		return nil, err
+	}
+	attrs := []asn1.RawValue{}
+	if len(template.ExtraExtensions) > 0 {
+		exts, err := asn1.Marshal(template.ExtraExtensions)
+		if err != nil {
+			return nil, err
+		}
+		attr, err := asn1.Marshal(csrAttribute{
+			Type:   oidExtensionRequest,
+			Values: []asn1.RawValue{{FullBytes: exts}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, asn1.RawValue{FullBytes: attr})
+	}
+	tbs, err := asn1.Marshal(tbsCertificateRequest{
+		Subject:       subject,
+		PublicKey:     spki,
+		RawAttributes: attrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signature, err := sign(rand, prv, sigAlgo, tbs)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(certificateRequest{
+		TBSCSR:             tbsCertificateRequest{Raw: tbs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: sigAlgo.OID()},
+		SignatureValue:     signature,
+	})
+}
+
+// Parse DER encoded PKCS#10 certificate signing request. GOST R 34.10
+// public key is validated.
+func ParseCertificateRequest(der []byte) (*CertificateRequest, error) {
+	var req certificateRequest
+	rest, err := asn1.Unmarshal(der, &req)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after certificate request")
+	}
+	tbs := &req.TBSCSR
+	csr := CertificateRequest{
+		Raw:                      der,
+		RawTBSCertificateRequest: tbs.Raw,
+		RawSubjectPublicKeyInfo:  tbs.PublicKey.Raw,
+		RawSubject:               tbs.Subject.FullBytes,
+		Version:                  tbs.Version,
+		Signature:                req.SignatureValue.RightAlign(),
+		SignatureAlgorithm:       oid.AlgorithmByOID(req.SignatureAlgorithm.Algorithm),
+	}
+	if csr.PublicKey, csr.PublicKeyAlgorithm, err = parseKeyInfo(&tbs.PublicKey); err != nil {
+		return nil, err
+	}
+	if csr.Subject, err = parseName(csr.RawSubject); err != nil {
+		return nil, err
+	}
+	for _, rawAttr := range tbs.RawAttributes {
+		var attr csrAttribute
+		if _, err = asn1.Unmarshal(rawAttr.FullBytes, &attr); err != nil {
+			return nil, err
+		}
+		if !attr.Type.Equal(oidExtensionRequest) || len(attr.Values) != 1 {
+			continue
+		}
+		if _, err = asn1.Unmarshal(attr.Values[0].FullBytes, &csr.Extensions); err != nil {
+			return nil, err
+		}
+	}
+	return &csr, nil
+}
+
+// Verify request's signature made by its own key.
+func (csr *CertificateRequest) CheckSignature() error {
+	return checkSignature(
+		csr.PublicKey,
+		csr.PublicKeyAlgorithm,
+		csr.SignatureAlgorithm,
+		csr.RawTBSCertificateRequest,
+		csr.Signature,
+	)
+}
@@ -0,0 +1,135 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+func TestCertificateRequestSymmetric(t *testing.T) {
+	for _, key := range []*testKey{
+		newTestKey2001(t),
+		newTestKey2012256(t),
+		newTestKey2012512(t),
+	} {
+		ext := testExtension(t, oidExtensionExtendedKeyUsage, false, []asn1.ObjectIdentifier{
+			{1, 3, 6, 1, 5, 5, 7, 3, 4},
+		})
+		der, err := CreateCertificateRequest(rand.Reader, &CertificateRequest{
+			Subject:            pkix.Name{CommonName: "Test", Organization: []string{"Org"}},
+			ExtraExtensions:    []pkix.Extension{ext},
+			PublicKeyAlgorithm: key.algo,
+			SignatureAlgorithm: key.sigAlgo,
+		}, key.prv)
+		if err != nil {
+			t.FailNow()
+		}
+		csr, err := ParseCertificateRequest(der)
+		if err != nil ||
+			csr.Version != 0 ||
+			csr.Subject.CommonName != "Test" ||
+			csr.Subject.Organization[0] != "Org" ||
+			csr.PublicKeyAlgorithm != key.algo ||
+			csr.SignatureAlgorithm != key.sigAlgo ||
+			!csr.PublicKey.Equal(key.pub) ||
+			len(csr.Extensions) != 1 ||
+			!csr.Extensions[0].Id.Equal(ext.Id) ||
+			bytes.Compare(csr.Extensions[0].Value, ext.Value) != 0 {
+			t.FailNow()
+		}
+		if csr.CheckSignature() != nil {
+			t.FailNow()
+		}
+		csr.Signature[len(csr.Signature)-1] ^= 0x01
+		if csr.CheckSignature() == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestCertificateRequestDefaults(t *testing.T) {
+	key := newTestKey2012256(t)
+	der, err := CreateCertificateRequest(rand.Reader, &CertificateRequest{
+		Subject: pkix.Name{CommonName: "Test"},
+	}, key.prv)
+	if err != nil {
+		t.FailNow()
+	}
+	csr, err := ParseCertificateRequest(der)
+	if err != nil ||
+		csr.PublicKeyAlgorithm != oid.GostR34102012256 ||
+		csr.SignatureAlgorithm != oid.GostR34102012256WithGostR34112012256 ||
+		len(csr.Extensions) != 0 ||
+		csr.CheckSignature() != nil {
+		t.FailNow()
+	}
+	_, err = CreateCertificateRequest(rand.Reader, &CertificateRequest{
+		SignatureAlgorithm: oid.GostR34102012512WithGostR34112012512,
+	}, key.prv)
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCertificateFromRequest(t *testing.T) {
+	caKey := newTestKey2012512(t)
+	ca := makeTestCertificate(t, 1, "CA", "CA", caKey, caKey, testCAExtensions(t, -1))
+	key := newTestKey2001(t)
+	der, err := CreateCertificateRequest(rand.Reader, &CertificateRequest{
+		Subject:            pkix.Name{CommonName: "Leaf"},
+		PublicKeyAlgorithm: key.algo,
+		SignatureAlgorithm: key.sigAlgo,
+	}, key.prv)
+	if err != nil {
+		t.FailNow()
+	}
+	csr, err := ParseCertificateRequest(der)
+	if err != nil || csr.CheckSignature() != nil {
+		t.FailNow()
+	}
+	der, err = CreateCertificate(rand.Reader, &Certificate{
+		SerialNumber:       big.NewInt(2),
+		RawSubject:         csr.RawSubject,
+		NotBefore:          testNotBefore,
+		NotAfter:           testNotAfter,
+		PublicKeyAlgorithm: csr.PublicKeyAlgorithm,
+	}, ca, csr.PublicKey, caKey.prv)
+	if err != nil {
+		t.FailNow()
+	}
+	leaf, err := ParseCertificate(der)
+	if err != nil ||
+		leaf.Subject.CommonName != "Leaf" ||
+		leaf.PublicKeyAlgorithm != oid.GostR34102001 ||
+		bytes.Compare(leaf.RawSubjectPublicKeyInfo, csr.RawSubjectPublicKeyInfo) != 0 {
+		t.FailNow()
+	}
+	if _, err = leaf.Verify(VerifyOptions{
+		Roots:       []*Certificate{ca},
+		CurrentTime: testNow,
+	}); err != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,160 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+// X.509 related structures with GOST algorithms: public and private
+// keys encoding, certificates and CRLs (RFC 4491, RFC 9215).
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+// GostR3410-2001-PublicKeyParameters and
+// GostR3410-2012-PublicKeyParameters.
+type publicKeyParams struct {
+	PublicKeyParamSet  asn1.ObjectIdentifier
+	DigestParamSet     asn1.ObjectIdentifier `asn1:"optional"`
+	EncryptionParamSet asn1.ObjectIdentifier `asn1:"optional"`
+}
+
+type subjectPublicKeyInfo struct {
+	Raw       asn1.RawContent
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// Key mode for the public key algorithm.
+func algorithmMode(algo oid.Algorithm) (gost3410.Mode, error) {
+	switch algo {
+	case oid.GostR34102001, oid.GostR34102012256:
+		return gost3410.Mode2001, nil
+	case oid.GostR34102012512:
+		return gost3410.Mode2012, nil
+	}
+	return 0, errors.New("Unsupported public key algorithm")
+}
+
+func marshalKeyAlgorithm(c *gost3410.Curve, mode gost3410.Mode, algo oid.Algorithm) (pkix.AlgorithmIdentifier, error) {
+	var ai pkix.AlgorithmIdentifier
+	algoMode, err := algorithmMode(algo)
+	if err != nil {
+		return ai, err
+	}
+	if algoMode != mode {
+		return ai, errors.New("Key mode does not match algorithm")
+	}
+	params := publicKeyParams{PublicKeyParamSet: oid.CurveOID(c)}
+	if params.PublicKeyParamSet == nil {
+		return ai, errors.New("Unknown curve")
+	}
+	switch algo {
+	case oid.GostR34102001:
+		params.DigestParamSet = oid.SboxOID(&gost28147.GostR3411_94_CryptoProParamSet)
+	case oid.GostR34102012256:
+		// Only the curves originally defined for 34.10-2001 are
+		// accompanied with the digest parameters
+		if !oid.CurveParamsOID(&gost3410.CurveParamsGostR34102012TC26ParamSetA256).Equal(params.PublicKeyParamSet) {
+			params.DigestParamSet = oid.GostR34112012256.OID()
+		}
+	}
+	paramsRaw, err := asn1.Marshal(params)
+	if err != nil {
+		return ai, err
+	}
+	ai.Algorithm = algo.OID()
+	ai.Parameters = asn1.RawValue{FullBytes: paramsRaw}
+	return ai, nil
+}
+
+func parseKeyAlgorithm(ai pkix.AlgorithmIdentifier) (*gost3410.Curve, gost3410.Mode, oid.Algorithm, error) {
+	algo := oid.AlgorithmByOID(ai.Algorithm)
+	mode, err := algorithmMode(algo)
+	if err != nil {
+		return nil, 0, algo, err
+	}
+	var params publicKeyParams
+	rest, err := asn1.Unmarshal(ai.Parameters.FullBytes, &params)
+	if err != nil {
+		return nil, 0, algo, err
+	}
+	if len(rest) > 0 {
+		return nil, 0, algo, errors.New("Trailing data after key parameters")
+	}
+	c, err := oid.CurveByOID(params.PublicKeyParamSet)
+	if err != nil {
+		return nil, 0, algo, err
+	}
+	return c, mode, algo, nil
+}
+
+// Marshal public key to DER encoded SubjectPublicKeyInfo. Algorithm
+// must be one of oid.GostR34102001, oid.GostR34102012256 or
+// oid.GostR34102012512 and correspond to the key's mode.
+func MarshalPKIXPublicKey(pub *gost3410.PublicKey, algo oid.Algorithm) ([]byte, error) {
+	ai, err := marshalKeyAlgorithm(pub.Curve(), pub.Mode(), algo)
+	if err != nil {
+		return nil, err
+	}
+	keyRaw, err := asn1.Marshal(pub.Raw())
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: ai,
+		PublicKey: asn1.BitString{Bytes: keyRaw, BitLength: 8 * len(keyRaw)},
+	})
+}
+
+func parsePublicKey(ai pkix.AlgorithmIdentifier, keyRaw []byte) (*gost3410.PublicKey, oid.Algorithm, error) {
+	c, mode, algo, err := parseKeyAlgorithm(ai)
+	if err != nil {
+		return nil, algo, err
+	}
+	var raw []byte
+	rest, err := asn1.Unmarshal(keyRaw, &raw)
+	if err != nil {
+		return nil, algo, err
+	}
+	if len(rest) > 0 {
+		return nil, algo, errors.New("Trailing data after public key")
+	}
+	pub, err := gost3410.NewPublicKeyValidated(c, mode, raw)
+	if err != nil {
+		return nil, algo, err
+	}
+	return pub, algo, nil
+}
+
+// Parse DER encoded SubjectPublicKeyInfo. Public key algorithm is also
+// returned. Key is validated.
+func ParsePKIXPublicKey(der []byte) (*gost3410.PublicKey, oid.Algorithm, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, oid.Unknown, err
+	}
+	if len(rest) > 0 {
+		return nil, oid.Unknown, errors.New("Trailing data after public key info")
+	}
+	return parsePublicKey(spki.Algorithm, spki.PublicKey.RightAlign())
+}
@@ -0,0 +1,155 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+var keysTestCases []struct {
+	params *gost3410.CurveParams
+	mode   gost3410.Mode
+	algo   oid.Algorithm
+} = []struct {
+	params *gost3410.CurveParams
+	mode   gost3410.Mode
+	algo   oid.Algorithm
+}{
+	{&gost3410.CurveParamsGostR34102001CryptoProA, gost3410.Mode2001, oid.GostR34102001},
+	{&gost3410.CurveParamsGostR34102001CryptoProXchA, gost3410.Mode2001, oid.GostR34102012256},
+	{&gost3410.CurveParamsGostR34102012TC26ParamSetA256, gost3410.Mode2001, oid.GostR34102012256},
+	{&gost3410.CurveParamsGostR34102012TC26ParamSetA, gost3410.Mode2012, oid.GostR34102012512},
+	{&gost3410.CurveParamsGostR34102012TC26ParamSetC, gost3410.Mode2012, oid.GostR34102012512},
+}
+
+func TestKeysSymmetric(t *testing.T) {
+	for _, tc := range keysTestCases {
+		c, _ := gost3410.NewCurveFromParams(*tc.params)
+		prv, err := gost3410.GenPrivateKey(c, tc.mode, rand.Reader)
+		if err != nil {
+			t.FailNow()
+		}
+		pub, _ := prv.PublicKey()
+		der, err := MarshalPKIXPublicKey(pub, tc.algo)
+		if err != nil {
+			t.FailNow()
+		}
+		pubParsed, algo, err := ParsePKIXPublicKey(der)
+		if err != nil || algo != tc.algo || !pubParsed.Equal(pub) {
+			t.FailNow()
+		}
+		der, err = MarshalPKCS8PrivateKey(prv, tc.algo)
+		if err != nil {
+			t.FailNow()
+		}
+		prvParsed, algo, err := ParsePKCS8PrivateKey(der)
+		if err != nil || algo != tc.algo || !prvParsed.Equal(prv) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestKeysStructure(t *testing.T) {
+	c, _ := gost3410.NewCurveFromParams(gost3410.CurveParamsGostR34102001CryptoProA)
+	prv, _ := gost3410.GenPrivateKey(c, gost3410.Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	der, _ := MarshalPKIXPublicKey(pub, oid.GostR34102012256)
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		t.FailNow()
+	}
+	if !spki.Algorithm.Algorithm.Equal(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 1}) {
+		t.FailNow()
+	}
+	var params publicKeyParams
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &params); err != nil {
+		t.FailNow()
+	}
+	if !params.PublicKeyParamSet.Equal(asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 1}) {
+		t.FailNow()
+	}
+	if !params.DigestParamSet.Equal(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 2}) {
+		t.FailNow()
+	}
+	var raw []byte
+	if _, err := asn1.Unmarshal(spki.PublicKey.Bytes, &raw); err != nil || len(raw) != 64 {
+		t.FailNow()
+	}
+}
+
+func TestKeysModeMismatch(t *testing.T) {
+	c, _ := gost3410.NewCurveFromParams(gost3410.CurveParamsGostR34102001CryptoProA)
+	prv, _ := gost3410.GenPrivateKey(c, gost3410.Mode2001, rand.Reader)
+	pub, _ := prv.PublicKey()
+	if _, err := MarshalPKIXPublicKey(pub, oid.GostR34102012512); err == nil {
+		t.FailNow()
+	}
+	if _, err := MarshalPKCS8PrivateKey(prv, oid.GostR34112012256); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestPKCS8PrivateKeyFormats(t *testing.T) {
+	c, _ := gost3410.NewCurveFromParams(gost3410.CurveParamsGostR34102001CryptoProA)
+	prv, _ := gost3410.GenPrivateKey(c, gost3410.Mode2001, rand.Reader)
+	ai, _ := marshalKeyAlgorithm(c, gost3410.Mode2001, oid.GostR34102012256)
+	raw := prv.Raw()
+	be := make([]byte, len(raw))
+	copy(be, raw)
+	reverse(be)
+	integer, _ := asn1.Marshal(big.NewInt(0).SetBytes(be))
+	for _, value := range [][]byte{integer, raw} {
+		der, _ := asn1.Marshal(pkcs8{Algo: ai, PrivateKey: value})
+		prvParsed, _, err := ParsePKCS8PrivateKey(der)
+		if err != nil || !prvParsed.Equal(prv) {
+			t.FailNow()
+		}
+	}
+	// OneAsymmetricKey with the public key
+	pub, _ := prv.PublicKey()
+	value, _ := asn1.Marshal(raw)
+	der, _ := asn1.Marshal(struct {
+		Version    int
+		Algo       pkix.AlgorithmIdentifier
+		PrivateKey []byte
+		PublicKey  asn1.BitString `asn1:"optional,tag:1"`
+	}{1, ai, value, asn1.BitString{Bytes: pub.Raw(), BitLength: 8 * 64}})
+	prvParsed, _, err := ParsePKCS8PrivateKey(der)
+	if err != nil || !prvParsed.Equal(prv) {
+		t.FailNow()
+	}
+}
+
+func TestPKCS8PrivateKeyTooLong(t *testing.T) {
+	c, _ := gost3410.NewCurveFromParams(gost3410.CurveParamsGostR34102001CryptoProA)
+	ai, _ := marshalKeyAlgorithm(c, gost3410.Mode2001, oid.GostR34102012256)
+	raw := make([]byte, int(gost3410.Mode2001)+1)
+	raw[0] = 0x01
+	value, _ := asn1.Marshal(raw)
+	der, _ := asn1.Marshal(pkcs8{Algo: ai, PrivateKey: value})
+	if _, _, err := ParsePKCS8PrivateKey(der); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,319 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/acpkm"
+	"github.com/martinlindhe/gogost/gost28147"
+	"github.com/martinlindhe/gogost/oid"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Recommended number of PBKDF2 iterations.
+	PBKDF2Iterations = 2000
+
+	// Maximal accepted number of PBKDF2 iterations, limiting CPU time
+	// spent on untrusted input.
+	PBKDF2MaxIterations = 1 << 18
+
+	// CTR-ACPKM section sizes for content encryption
+	KuznyechikSectionSize = 256 * 1024
+	MagmaSectionSize      = 8 * 1024
+
+	pbes2SaltSize = 32
+)
+
+var (
+	oidPBES2  asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 asn1.ObjectIdentifier = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+)
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// Gost28147-89-Parameters
+type gost2814789Params struct {
+	IV                 []byte
+	EncryptionParamSet asn1.ObjectIdentifier
+}
+
+// Gost3412-15-Encryption-Parameters
+type gost341215Params struct {
+	UKM []byte
+}
+
+// Symmetric encryption scheme with its parameters: 28147-89 in CFB
+// mode with CryptoPro key meshing (oid.Gost2814789), Kuznyechik or
+// Magma in CTR-ACPKM mode (oid.KuznyechikCTRACPKM, oid.MagmaCTRACPKM).
+// All of them use 32-byte keys.
+type EncryptionScheme struct {
+	Algorithm oid.Algorithm
+	IV        []byte          // UKM for CTR-ACPKM: half of the block
+	Sbox      *gost28147.Sbox // 28147-89 only
+}
+
+func ivSize(algo oid.Algorithm) (int, error) {
+	switch algo {
+	case oid.Gost2814789:
+		return gost28147.BlockSize, nil
+	case oid.KuznyechikCTRACPKM:
+		return 8, nil
+	case oid.MagmaCTRACPKM:
+		return 4, nil
+	}
+	return 0, errors.New("Unsupported encryption scheme")
+}
+
+// Create encryption scheme with random IV. 28147-89 uses
+// id-tc26-gost-28147-param-Z S-box.
+func NewEncryptionScheme(algo oid.Algorithm, rand io.Reader) (*EncryptionScheme, error) {
+	size, err := ivSize(algo)
+	if err != nil {
+		return nil, err
+	}
+	es := EncryptionScheme{Algorithm: algo, IV: make([]byte, size)}
+	if _, err = io.ReadFull(rand, es.IV); err != nil {
+		return nil, err
+	}
+	if algo == oid.Gost2814789 {
+		es.Sbox = &gost28147.Gost28147_tc26_ParamZ
+	}
+	return &es, nil
+}
+
+// Parse encryption scheme's AlgorithmIdentifier.
+func ParseEncryptionScheme(ai pkix.AlgorithmIdentifier) (*EncryptionScheme, error) {
+	es := EncryptionScheme{Algorithm: oid.AlgorithmByOID(ai.Algorithm)}
+	size, err := ivSize(es.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var rest []byte
+	if es.Algorithm == oid.Gost2814789 {
+		var params gost2814789Params
+		if rest, err = asn1.Unmarshal(ai.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		if es.Sbox = oid.SboxByOID(params.EncryptionParamSet); es.Sbox == nil {
+			return nil, errors.New("Unknown S-box")
+		}
+		es.IV = params.IV
+	} else {
+		var params gost341215Params
+		if rest, err = asn1.Unmarshal(ai.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		es.IV = params.UKM
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after encryption parameters")
+	}
+	if len(es.IV) != size {
+		return nil, errors.New("Invalid IV length")
+	}
+	return &es, nil
+}
+
+// Marshal encryption scheme to AlgorithmIdentifier.
+func (es *EncryptionScheme) AlgorithmIdentifier() (pkix.AlgorithmIdentifier, error) {
+	ai := pkix.AlgorithmIdentifier{Algorithm: es.Algorithm.OID()}
+	var raw []byte
+	var err error
+	if es.Algorithm == oid.Gost2814789 {
+		raw, err = asn1.Marshal(gost2814789Params{es.IV, oid.SboxOID(es.Sbox)})
+	} else {
+		raw, err = asn1.Marshal(gost341215Params{es.IV})
+	}
+	ai.Parameters = asn1.RawValue{FullBytes: raw}
+	return ai, err
+}
+
+func (es *EncryptionScheme) xorKeyStream(key, dst, src []byte, encrypt bool) error {
+	var ctr *acpkm.CTR
+	var err error
+	switch es.Algorithm {
+	case oid.KuznyechikCTRACPKM:
+		ctr, err = acpkm.NewCTR(acpkm.NewKuznechik, key, es.IV, KuznyechikSectionSize)
+	case oid.MagmaCTRACPKM:
+		ctr, err = acpkm.NewCTR(acpkm.NewMagma, key, es.IV, MagmaSectionSize)
+	case oid.Gost2814789:
+		if len(key) != gost28147.KeySize {
+			return errors.New("Invalid key length")
+		}
+		var k [gost28147.KeySize]byte
+		var iv [gost28147.BlockSize]byte
+		copy(k[:], key)
+		copy(iv[:], es.IV)
+		c := gost28147.NewCipher(k, es.Sbox)
+		if encrypt {
+			c.NewCFBEncrypterMesh(iv).XORKeyStream(dst, src)
+		} else {
+			c.NewCFBDecrypterMesh(iv).XORKeyStream(dst, src)
+		}
+		return nil
+	default:
+		return errors.New("Unsupported encryption scheme")
+	}
+	if err != nil {
+		return err
+	}
+	ctr.XORKeyStream(dst, src)
+	return nil
+}
+
+// Encrypt src to dst with the key.
+func (es *EncryptionScheme) Encrypt(key, dst, src []byte) error {
+	return es.xorKeyStream(key, dst, src, true)
+}
+
+// Decrypt src to dst with the key.
+func (es *EncryptionScheme) Decrypt(key, dst, src []byte) error {
+	return es.xorKeyStream(key, dst, src, false)
+}
+
+// Encrypt data with the password using PBES2 with PBKDF2 over
+// HMAC-Streebog-512 and the encryption scheme algorithm. Returned
+// AlgorithmIdentifier holds all parameters required for decryption.
+func EncryptPBES2(
+	algo oid.Algorithm,
+	password, data []byte,
+	iterations int,
+	rand io.Reader,
+) (pkix.AlgorithmIdentifier, []byte, error) {
+	var ai pkix.AlgorithmIdentifier
+	if iterations <= 0 || iterations > PBKDF2MaxIterations {
+		return ai, nil, errors.New("Invalid iterations count")
+	}
+	es, err := NewEncryptionScheme(algo, rand)
+	if err != nil {
+		return ai, nil, err
+	}
+	salt := make([]byte, pbes2SaltSize)
+	if _, err = io.ReadFull(rand, salt); err != nil {
+		return ai, nil, err
+	}
+	esAI, err := es.AlgorithmIdentifier()
+	if err != nil {
+		return ai, nil, err
+	}
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: iterations,
+		KeyLength:      acpkm.KeySize,
+		PRF: pkix.AlgorithmIdentifier{
+			Algorithm:  oid.HMACGostR34112012512.OID(),
+			Parameters: asn1.NullRawValue,
+		},
+	})
+	if err != nil {
+		return ai, nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBKDF2,
+			Parameters: asn1.RawValue{FullBytes: kdfParams},
+		},
+		EncryptionScheme: esAI,
+	})
+	if err != nil {
+		return ai, nil, err
+	}
+	key := pbkdf2.Key(
+		password,
+		salt,
+		iterations,
+		acpkm.KeySize,
+		oid.HMACGostR34112012512.NewHash,
+	)
+	encrypted := make([]byte, len(data))
+	if err = es.Encrypt(key, encrypted, data); err != nil {
+		return ai, nil, err
+	}
+	ai.Algorithm = oidPBES2
+	ai.Parameters = asn1.RawValue{FullBytes: params}
+	return ai, encrypted, nil
+}
+
+// Decrypt PBES2 encrypted data with the password. Any HMAC-Streebog
+// PBKDF2 pseudorandom function and encryption scheme supported by
+// EncryptionScheme are accepted.
+func DecryptPBES2(ai pkix.AlgorithmIdentifier, password, data []byte) ([]byte, error) {
+	if !ai.Algorithm.Equal(oidPBES2) {
+		return nil, errors.New("Unsupported encryption algorithm")
+	}
+	var params pbes2Params
+	rest, err := asn1.Unmarshal(ai.Parameters.FullBytes, &params)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after PBES2 parameters")
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.New("Unsupported key derivation function")
+	}
+	var kdfParams pbkdf2Params
+	rest, err = asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after PBKDF2 parameters")
+	}
+	prf := oid.AlgorithmByOID(kdfParams.PRF.Algorithm)
+	if prf != oid.HMACGostR34112012256 && prf != oid.HMACGostR34112012512 {
+		return nil, errors.New("Unsupported pseudorandom function")
+	}
+	if kdfParams.IterationCount <= 0 || kdfParams.IterationCount > PBKDF2MaxIterations {
+		return nil, errors.New("Invalid iterations count")
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != acpkm.KeySize {
+		return nil, errors.New("Invalid key length")
+	}
+	es, err := ParseEncryptionScheme(params.EncryptionScheme)
+	if err != nil {
+		return nil, err
+	}
+	key := pbkdf2.Key(
+		password,
+		kdfParams.Salt,
+		kdfParams.IterationCount,
+		acpkm.KeySize,
+		prf.NewHash,
+	)
+	decrypted := make([]byte, len(data))
+	if err = es.Decrypt(key, decrypted, data); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,94 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"encoding/pem"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+const (
+	PEMPublicKey           = "PUBLIC KEY"
+	PEMPrivateKey          = "PRIVATE KEY"
+	PEMEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+)
+
+// Marshal public key to PEM encoded SubjectPublicKeyInfo.
+func MarshalPEMPublicKey(pub *gost3410.PublicKey, algo oid.Algorithm) ([]byte, error) {
+	der, err := MarshalPKIXPublicKey(pub, algo)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMPublicKey, Bytes: der}), nil
+}
+
+// Parse the first PEM encoded public key block.
+func ParsePEMPublicKey(data []byte) (*gost3410.PublicKey, oid.Algorithm, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, oid.Unknown, errors.New("No PEM public key found")
+		}
+		if block.Type == PEMPublicKey {
+			return ParsePKIXPublicKey(block.Bytes)
+		}
+	}
+}
+
+// Marshal private key to PEM encoded PKCS#8.
+func MarshalPEMPrivateKey(prv *gost3410.PrivateKey, algo oid.Algorithm) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKey(prv, algo)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMPrivateKey, Bytes: der}), nil
+}
+
+// Marshal private key to PEM encoded password encrypted PKCS#8 (see
+// EncryptPKCS8PrivateKey). Use PBKDF2Iterations unless you need a
+// different cost.
+func MarshalPEMEncryptedPrivateKey(prv *gost3410.PrivateKey, algo oid.Algorithm, password []byte, iterations int, rand io.Reader) ([]byte, error) {
+	der, err := EncryptPKCS8PrivateKey(prv, algo, oid.Unknown, password, iterations, rand)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMEncryptedPrivateKey, Bytes: der}), nil
+}
+
+// Parse the first PEM encoded private key block, either plain or
+// encrypted. Password is used only with encrypted ones.
+func ParsePEMPrivateKey(data, password []byte) (*gost3410.PrivateKey, oid.Algorithm, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, oid.Unknown, errors.New("No PEM private key found")
+		}
+		switch block.Type {
+		case PEMPrivateKey:
+			return ParsePKCS8PrivateKey(block.Bytes)
+		case PEMEncryptedPrivateKey:
+			return DecryptPKCS8PrivateKey(block.Bytes, password)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+func reverse(d []byte) {
+	for i, j := 0, len(d)-1; i < j; i, j = i+1, j-1 {
+		d[i], d[j] = d[j], d[i]
+	}
+}
+
+// Marshal private key to DER encoded PKCS#8 PrivateKeyInfo. Private key
+// is stored as little-endian OCTET STRING. Algorithm is the same as
+// for MarshalPKIXPublicKey.
+func MarshalPKCS8PrivateKey(prv *gost3410.PrivateKey, algo oid.Algorithm) ([]byte, error) {
+	ai, err := marshalKeyAlgorithm(prv.Curve(), prv.Mode(), algo)
+	if err != nil {
+		return nil, err
+	}
+	keyRaw, err := asn1.Marshal(prv.Raw())
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{Algo: ai, PrivateKey: keyRaw})
+}
+
+// Decode private key value, that can be little-endian OCTET STRING,
+// big-endian INTEGER or just raw little-endian bytes, as various
+// implementations do.
+func parsePrivateKeyValue(raw []byte, mode gost3410.Mode) ([]byte, error) {
+	var octets []byte
+	if rest, err := asn1.Unmarshal(raw, &octets); err == nil && len(rest) == 0 {
+		return octets, nil
+	}
+	var integer *big.Int
+	if rest, err := asn1.Unmarshal(raw, &integer); err == nil && len(rest) == 0 {
+		if integer.Sign() <= 0 || integer.BitLen() > 8*int(mode) {
+			return nil, errors.New("Invalid private key value")
+		}
+		octets = make([]byte, int(mode))
+		integer.FillBytes(octets)
+		reverse(octets)
+		return octets, nil
+	}
+	if len(raw) == int(mode) {
+		return raw, nil
+	}
+	return nil, errors.New("Invalid private key value")
+}
+
+// Parse DER encoded PKCS#8 PrivateKeyInfo. Public key algorithm is
+// also returned.
+func ParsePKCS8PrivateKey(der []byte) (*gost3410.PrivateKey, oid.Algorithm, error) {
+	var p pkcs8
+	rest, err := asn1.Unmarshal(der, &p)
+	if err != nil {
+		return nil, oid.Unknown, err
+	}
+	if len(rest) > 0 {
+		return nil, oid.Unknown, errors.New("Trailing data after private key info")
+	}
+	if p.Version != 0 && p.Version != 1 {
+		return nil, oid.Unknown, errors.New("Unsupported private key info version")
+	}
+	c, mode, algo, err := parseKeyAlgorithm(p.Algo)
+	if err != nil {
+		return nil, algo, err
+	}
+	raw, err := parsePrivateKeyValue(p.PrivateKey, mode)
+	if err != nil {
+		return nil, algo, err
+	}
+	prv, err := gost3410.NewPrivateKey(c, mode, raw)
+	if err != nil {
+		return nil, algo, err
+	}
+	return prv, algo, nil
+}
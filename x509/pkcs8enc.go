@@ -0,0 +1,77 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/martinlindhe/gogost/gost3410"
+	"github.com/martinlindhe/gogost/oid"
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algo          pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// Encrypt DER encoded PKCS#8 private key with the password and create
+// DER encoded EncryptedPrivateKeyInfo. PBES2 is used with PBKDF2 over
+// HMAC-Streebog-512 and the scheme algorithm (see EncryptionScheme).
+// oid.Unknown scheme means 28147-89 in CFB mode with CryptoPro key
+// meshing and id-tc26-gost-28147-param-Z S-box, as gost-engine does.
+func EncryptPKCS8PrivateKey(
+	prv *gost3410.PrivateKey,
+	algo, scheme oid.Algorithm,
+	password []byte,
+	iterations int,
+	rand io.Reader,
+) ([]byte, error) {
+	if scheme == oid.Unknown {
+		scheme = oid.Gost2814789
+	}
+	der, err := MarshalPKCS8PrivateKey(prv, algo)
+	if err != nil {
+		return nil, err
+	}
+	ai, encrypted, err := EncryptPBES2(scheme, password, der, iterations, rand)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{ai, encrypted})
+}
+
+// Decrypt DER encoded EncryptedPrivateKeyInfo made by
+// EncryptPKCS8PrivateKey and parse the private key (see DecryptPBES2).
+func DecryptPKCS8PrivateKey(der, password []byte) (*gost3410.PrivateKey, oid.Algorithm, error) {
+	var info encryptedPrivateKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, oid.Unknown, err
+	}
+	if len(rest) > 0 {
+		return nil, oid.Unknown, errors.New("Trailing data after encrypted private key info")
+	}
+	data, err := DecryptPBES2(info.Algo, password, info.EncryptedData)
+	if err != nil {
+		return nil, oid.Unknown, err
+	}
+	return ParsePKCS8PrivateKey(data)
+}
@@ -0,0 +1,149 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/martinlindhe/gogost/oid"
+)
+
+// Certificates and CRL below are DER encoded by hand and signed with
+// GNU Nettle 3.8 gostdsa_sign over its own Streebog implementation: CA
+// has 34.10-2012 512-bit key on tc26-512-paramSetA, leaf has 256-bit
+// one on CryptoPro-A with explicit digest parameters, CRL revokes
+// serial 0x4a1d.
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var (
+	nettleCA []byte = hexDecode("" +
+		"3082021c30820188a00302010202024a1b300a06082a85030701010303303931" +
+		"0b3009060355040613025255310f300d060355040a0c06476f474f5354311930" +
+		"1706035504030c10476f474f5354204e6574746c65204341301e170d32303031" +
+		"30313030303030305a170d3339313233313233353935395a3039310b30090603" +
+		"55040613025255310f300d060355040a0c06476f474f53543119301706035504" +
+		"030c10476f474f5354204e6574746c652043413081a0301706082a8503070101" +
+		"0102300b06092a85030701020102010381840004818098ec54e2f3ff78ab7bea" +
+		"d6364517585ce4a49f7e8a779780bb18370c01e0163a53de29ca076371450bab" +
+		"f42f8d79d6371080d7a0e7ecfc592461c4156c1330fe0d9c1e7e5f980a5dc98b" +
+		"e063122c02437441d762c890fa97175afd86190e784fa1fcc9d440fe1e93ca37" +
+		"6994bacb030e1fc1329d7b1570435f12e4fe4a18e879a3383036300f0603551d" +
+		"130101ff040530030101ff300e0603551d0f0101ff0404030201063013060355" +
+		"1d0e040c040a7f3a1c5e9b2d4f6a8c0e300a06082a8503070101030303818100" +
+		"7784909ab4b057807c01528628584b7f3c5472150931a04742dfd0a65f0e8232" +
+		"6e961b14f2a11d6c6a5ceda3ea72d74a7a8c5b0e2986e3d67920714f36fc053b" +
+		"5b76286c139fcca32955da8231b411a0ee3ae11bfa86d77b017e8eed0567321d" +
+		"a7c2da4a4cde8acc94161b293bc0e436efa75242fdb1e8a8555cd92ae93b2d04",
+	)
+
+	nettleLeaf []byte = hexDecode("" +
+		"308201e930820155a00302010202024a1c300a06082a85030701010303303931" +
+		"0b3009060355040613025255310f300d060355040a0c06476f474f5354311930" +
+		"1706035504030c10476f474f5354204e6574746c65204341301e170d32303031" +
+		"30313030303030305a170d3339313233313233353935395a303b310b30090603" +
+		"55040613025255310f300d060355040a0c06476f474f5354311b301906035504" +
+		"030c12476f474f5354204e6574746c65204c6561663066301f06082a85030701" +
+		"010101301306072a85030202230106082a8503070101020203430004406ee214" +
+		"d54d40ec2dd55622be9d26559aa9203792d9437e268e61512513e60f40508f53" +
+		"38c39099791fe66e69510091073f0ad302bae77db7ed93e2e7acfd8d53a33e30" +
+		"3c300e0603551d0f0101ff04040302078030130603551d0e040c040a11223344" +
+		"55667788990030150603551d23040e300c800a7f3a1c5e9b2d4f6a8c0e300a06" +
+		"082a850307010103030381810054e4615761cd157fd21cc8d65ab82b5fbfc012" +
+		"c577612ceaeb51741ee19b8ba6c258f37771ad484efd610a725b622f61391d79" +
+		"c2ce54b8208b586700070e562a75422c6debbccff398b575f5fc0bb4e5805fcc" +
+		"5515af6eae2a3ad96c13387b4a2c71b7abc9c7cbef2a3edc280844e3f7642b00" +
+		"5cc699b03caa552461b7635ce9",
+	)
+
+	nettleCRL []byte = hexDecode("" +
+		"30820111307f020101300a06082a850307010103033039310b30090603550406" +
+		"13025255310f300d060355040a0c06476f474f53543119301706035504030c10" +
+		"476f474f5354204e6574746c65204341170d3230303630313030303030305a17" +
+		"0d3339313233313233353935395a3015301302024a1d170d3230303530313030" +
+		"303030305a300a06082a85030701010303038181005ea61c53b8062b59a8e341" +
+		"4b0bd4d6a12ecf8041713457bf65538b2668fb5d9b582de191239a4915acf70b" +
+		"2a6bb43b296bdd4978550073aa23198a9255fccfbe2032e4bdd1c16f94978a1c" +
+		"dcc8f9283062ad5b0f243b33db07722ff6141949839c8d503b1144280940c397" +
+		"6feac5d101b28de4c1676874869087fbac20fcb863",
+	)
+
+	nettleLeafPub []byte = hexDecode("" +
+		"6ee214d54d40ec2dd55622be9d26559aa9203792d9437e268e61512513e60f40" +
+		"508f5338c39099791fe66e69510091073f0ad302bae77db7ed93e2e7acfd8d53",
+	)
+)
+
+func TestNettleCertificates(t *testing.T) {
+	ca, err := ParseCertificate(nettleCA)
+	if err != nil ||
+		ca.Subject.CommonName != "GoGOST Nettle CA" ||
+		ca.Subject.Organization[0] != "GoGOST" ||
+		ca.SerialNumber.Int64() != 0x4a1b ||
+		ca.SignatureAlgorithm != oid.GostR34102012512WithGostR34112012512 ||
+		ca.PublicKeyAlgorithm != oid.GostR34102012512 ||
+		!ca.IsCA ||
+		ca.KeyUsage != KeyUsageCertSign|KeyUsageCRLSign ||
+		ca.CheckSignatureFrom(ca) != nil {
+		t.FailNow()
+	}
+	leaf, err := ParseCertificate(nettleLeaf)
+	if err != nil ||
+		leaf.Subject.CommonName != "GoGOST Nettle Leaf" ||
+		leaf.PublicKeyAlgorithm != oid.GostR34102012256 ||
+		bytes.Compare(leaf.PublicKey.Raw(), nettleLeafPub) != 0 ||
+		leaf.KeyUsage != KeyUsageDigitalSignature ||
+		bytes.Compare(leaf.AuthorityKeyId, ca.SubjectKeyId) != 0 {
+		t.FailNow()
+	}
+	opts := VerifyOptions{
+		Roots:       []*Certificate{ca},
+		CurrentTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	chain, err := leaf.Verify(opts)
+	if err != nil || len(chain) != 2 {
+		t.FailNow()
+	}
+	crl, err := ParseRevocationList(nettleCRL)
+	if err != nil ||
+		crl.CheckSignatureFrom(ca) != nil ||
+		!crl.IsRevoked(big.NewInt(0x4a1d)) ||
+		crl.IsRevoked(leaf.SerialNumber) {
+		t.FailNow()
+	}
+	opts.CRLs = []*RevocationList{crl}
+	if _, err = leaf.Verify(opts); err != nil {
+		t.FailNow()
+	}
+	tampered := append([]byte{}, nettleLeaf...)
+	tampered[len(tampered)-1] ^= 0x01
+	leaf, err = ParseCertificate(tampered)
+	if err != nil || leaf.CheckSignatureFrom(ca) == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,134 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+const maxChainLen = 16
+
+// Certificate chain verification options. CRLs are checked only if
+// they are issued by the issuer of the checked certificate. Such CRL
+// with invalid signature, unhandled critical extensions, that is not
+// yet valid or whose NextUpdate has passed fails the verification.
+type VerifyOptions struct {
+	Roots         []*Certificate
+	Intermediates []*Certificate
+	CRLs          []*RevocationList
+	CurrentTime   time.Time // time.Now() if zero
+}
+
+func (c *Certificate) isValid(now time.Time) error {
+	if now.Before(c.NotBefore) || now.After(c.NotAfter) {
+		return errors.New("Certificate has expired or is not yet valid")
+	}
+	if len(c.UnhandledCriticalExtensions) > 0 {
+		return errors.New("Unhandled critical extension")
+	}
+	return nil
+}
+
+func checkRevocation(c, issuer *Certificate, opts *VerifyOptions, now time.Time) error {
+	for _, crl := range opts.CRLs {
+		if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return err
+		}
+		if len(crl.UnhandledCriticalExtensions) > 0 {
+			return errors.New("Unhandled critical CRL extension")
+		}
+		if crl.IsRevoked(c.SerialNumber) {
+			return errors.New("Certificate is revoked")
+		}
+		if !crl.IsCurrent(now) {
+			return errors.New("Certificate revocation list is outdated")
+		}
+	}
+	return nil
+}
+
+// Check that the parent has issued the last certificate in the chain.
+func checkParent(chain []*Certificate, parent *Certificate, opts *VerifyOptions, now time.Time) error {
+	c := chain[len(chain)-1]
+	if !bytes.Equal(c.RawIssuer, parent.RawSubject) {
+		return errors.New("Issuer name mismatch")
+	}
+	if err := parent.isValid(now); err != nil {
+		return err
+	}
+	if parent.MaxPathLen >= 0 && len(chain)-1 > parent.MaxPathLen {
+		return errors.New("Path length constraint violated")
+	}
+	if err := c.CheckSignatureFrom(parent); err != nil {
+		return err
+	}
+	return checkRevocation(c, parent, opts, now)
+}
+
+func buildChain(chain []*Certificate, opts *VerifyOptions, now time.Time) ([]*Certificate, error) {
+	err := errors.New("Unknown certification authority")
+	for _, root := range opts.Roots {
+		if err = checkParent(chain, root, opts, now); err == nil {
+			return append(chain, root), nil
+		}
+	}
+	if len(chain) == maxChainLen {
+		return nil, errors.New("Too long certificate chain")
+	}
+NextIntermediate:
+	for _, inter := range opts.Intermediates {
+		for _, c := range chain {
+			if c.Equal(inter) {
+				continue NextIntermediate
+			}
+		}
+		if checkParent(chain, inter, opts, now) != nil {
+			continue
+		}
+		var full []*Certificate
+		full, err = buildChain(append(chain, inter), opts, now)
+		if err == nil {
+			return full, nil
+		}
+	}
+	return nil, err
+}
+
+// Verify the certificate by building the chain up to one of the roots.
+// Chain starting with the certificate itself and ending with the root
+// is returned.
+func (c *Certificate) Verify(opts VerifyOptions) ([]*Certificate, error) {
+	now := opts.CurrentTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if err := c.isValid(now); err != nil {
+		return nil, err
+	}
+	for _, root := range opts.Roots {
+		if c.Equal(root) {
+			return []*Certificate{c}, nil
+		}
+	}
+	return buildChain([]*Certificate{c}, &opts, now)
+}
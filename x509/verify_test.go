@@ -0,0 +1,137 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2017 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this program.  If not, see
+// <http://www.gnu.org/licenses/>.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+	"time"
+)
+
+type testChain struct {
+	root, inter, leaf *Certificate
+	rootKey, interKey *testKey
+}
+
+func newTestChain(t *testing.T, rootPathLen int) *testChain {
+	rootKey := newTestKey2012512(t)
+	interKey := newTestKey2012256(t)
+	leafKey := newTestKey2001(t)
+	return &testChain{
+		root:     makeTestCertificate(t, 1, "Root", "Root", rootKey, rootKey, testCAExtensions(t, rootPathLen)),
+		inter:    makeTestCertificate(t, 2, "Inter", "Root", interKey, rootKey, testCAExtensions(t, 0)),
+		leaf:     makeTestCertificate(t, 3, "Leaf", "Inter", leafKey, interKey, nil),
+		rootKey:  rootKey,
+		interKey: interKey,
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	tc := newTestChain(t, -1)
+	chain, err := tc.leaf.Verify(VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.leaf, tc.root, tc.inter},
+		CurrentTime:   testNow,
+	})
+	if err != nil ||
+		len(chain) != 3 ||
+		chain[0] != tc.leaf ||
+		chain[1] != tc.inter ||
+		chain[2] != tc.root {
+		t.FailNow()
+	}
+	chain, err = tc.root.Verify(VerifyOptions{
+		Roots:       []*Certificate{tc.root},
+		CurrentTime: testNow,
+	})
+	if err != nil || len(chain) != 1 {
+		t.FailNow()
+	}
+}
+
+func TestVerifyNoIntermediate(t *testing.T) {
+	tc := newTestChain(t, -1)
+	if _, err := tc.leaf.Verify(VerifyOptions{
+		Roots:       []*Certificate{tc.root},
+		CurrentTime: testNow,
+	}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyTime(t *testing.T) {
+	tc := newTestChain(t, -1)
+	opts := VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+	}
+	for _, now := range []time.Time{
+		testNotBefore.Add(-time.Second),
+		testNotAfter.Add(time.Second),
+	} {
+		opts.CurrentTime = now
+		if _, err := tc.leaf.Verify(opts); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestVerifyWrongSigner(t *testing.T) {
+	tc := newTestChain(t, -1)
+	forged := makeTestCertificate(t, 3, "Leaf", "Inter", newTestKey2001(t), tc.rootKey, nil)
+	if _, err := forged.Verify(VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CurrentTime:   testNow,
+	}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyPathLen(t *testing.T) {
+	tc := newTestChain(t, 0)
+	if _, err := tc.leaf.Verify(VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CurrentTime:   testNow,
+	}); err == nil {
+		t.FailNow()
+	}
+	if _, err := tc.inter.Verify(VerifyOptions{
+		Roots:       []*Certificate{tc.root},
+		CurrentTime: testNow,
+	}); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyUnhandledCriticalExtension(t *testing.T) {
+	tc := newTestChain(t, -1)
+	leaf := makeTestCertificate(
+		t, 3, "Leaf", "Inter", newTestKey2001(t), tc.interKey,
+		[]pkix.Extension{testExtension(t, asn1.ObjectIdentifier{1, 2, 3}, true, 1)},
+	)
+	if _, err := leaf.Verify(VerifyOptions{
+		Roots:         []*Certificate{tc.root},
+		Intermediates: []*Certificate{tc.inter},
+		CurrentTime:   testNow,
+	}); err == nil {
+		t.FailNow()
+	}
+}